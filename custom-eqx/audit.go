@@ -0,0 +1,69 @@
+package equinix
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AuditSink receives a record of every mutating API request sent by the provider,
+// that is every request other than GET, HEAD and OPTIONS. The status code is zero
+// when the request failed without a response. Secrets are redacted from the URL.
+type AuditSink interface {
+	Record(service, method, url string, status int, at time.Time)
+}
+
+// redactedQueryParameters are the substrings of the names of query parameters whose
+// values are redacted from audited URLs.
+var redactedQueryParameters = []string{"token", "secret", "password", "key", "signature"}
+
+// auditTransport is a RoundTripper recording the mutating requests of a service to
+// an AuditSink.
+type auditTransport struct {
+	service string
+	sink    AuditSink
+	now     func() time.Time
+	next    http.RoundTripper
+}
+
+func newAuditTransport(service string, sink AuditSink, now func() time.Time, next http.RoundTripper) *auditTransport {
+	if now == nil {
+		now = time.Now
+	}
+	return &auditTransport{service: service, sink: sink, now: now, next: next}
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(req)
+	}
+	at := t.now()
+	resp, err := t.next.RoundTrip(req)
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	t.sink.Record(t.service, req.Method, redactURL(req.URL), status, at)
+	return resp, err
+}
+
+// Returns the URL without user credentials and with the values of query parameters
+// that may carry secrets replaced.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	query := redacted.Query()
+	for name := range query {
+		lowered := strings.ToLower(name)
+		for _, secret := range redactedQueryParameters {
+			if strings.Contains(lowered, secret) {
+				query[name] = []string{"REDACTED"}
+				break
+			}
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
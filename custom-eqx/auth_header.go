@@ -0,0 +1,68 @@
+package equinix
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+	xoauth2 "golang.org/x/oauth2"
+)
+
+const (
+	defaultAuthHeaderName   = "Authorization"
+	defaultAuthHeaderFormat = "Bearer %s"
+)
+
+func (c *Config) authHeaderName() string {
+	if c.AuthHeaderName != "" {
+		return c.AuthHeaderName
+	}
+	return defaultAuthHeaderName
+}
+
+func (c *Config) authHeaderFormat() string {
+	if c.AuthHeaderFormat != "" {
+		return c.AuthHeaderFormat
+	}
+	return defaultAuthHeaderFormat
+}
+
+func (c *Config) validateAuthHeader() error {
+	name := c.authHeaderName()
+	if !httpguts.ValidHeaderFieldName(name) {
+		return fmt.Errorf("'authHeaderName' must be a valid header name, got: %q", name)
+	}
+	format := c.authHeaderFormat()
+	if strings.Count(format, "%s") != 1 || strings.Count(format, "%") != 1 {
+		return fmt.Errorf("'authHeaderFormat' must contain exactly one %%s and no other verbs, got: %q", format)
+	}
+	for header := range c.ExtraHeaders {
+		if http.CanonicalHeaderKey(header) == http.CanonicalHeaderKey(name) {
+			return fmt.Errorf("'extraHeaders' cannot override the %q header", header)
+		}
+	}
+	return nil
+}
+
+// tokenHeaderTransport is a RoundTripper authorizing requests with the tokens of the
+// token source, presented in the given header with the given format.
+type tokenHeaderTransport struct {
+	source xoauth2.TokenSource
+	name   string
+	format string
+	next   http.RoundTripper
+}
+
+func (t *tokenHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(t.name, fmt.Sprintf(t.format, token.AccessToken))
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,87 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Load_authHeader(t *testing.T) {
+	// given
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	config := Config{
+		BaseURL:          server.URL,
+		Token:            "token",
+		AuthHeaderName:   "X-Gateway-Token",
+		AuthHeaderFormat: "Token token=%s",
+	}
+	assert.NoError(t, config.Load(context.Background()))
+	// when
+	_, err := config.ne.GetSSHPublicKeys()
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Token token=token", received.Get("X-Gateway-Token"), "Token is presented in the custom header")
+	assert.Empty(t, received.Get("Authorization"), "Authorization header is not set")
+}
+
+func TestConfig_Load_invalidAuthHeader(t *testing.T) {
+	testCases := []struct {
+		name         string
+		headerName   string
+		headerFormat string
+		extraHeaders map[string]string
+		expected     string
+	}{
+		{
+			name:         "NoVerb",
+			headerFormat: "Bearer",
+			expected:     `'authHeaderFormat' must contain exactly one %s and no other verbs, got: "Bearer"`,
+		},
+		{
+			name:         "TwoVerbs",
+			headerFormat: "%s %s",
+			expected:     `'authHeaderFormat' must contain exactly one %s and no other verbs, got: "%s %s"`,
+		},
+		{
+			name:         "OtherVerb",
+			headerFormat: "%d %s",
+			expected:     `'authHeaderFormat' must contain exactly one %s and no other verbs, got: "%d %s"`,
+		},
+		{
+			name:       "InvalidName",
+			headerName: "X Token",
+			expected:   `'authHeaderName' must be a valid header name, got: "X Token"`,
+		},
+		{
+			name:         "OverriddenByExtraHeaders",
+			headerName:   "X-Gateway-Token",
+			extraHeaders: map[string]string{"x-gateway-token": "other"},
+			expected:     `'extraHeaders' cannot override the "x-gateway-token" header`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			config := Config{
+				BaseURL:          "https://api.example.com",
+				Token:            "token",
+				AuthHeaderName:   testCase.headerName,
+				AuthHeaderFormat: testCase.headerFormat,
+				ExtraHeaders:     testCase.extraHeaders,
+			}
+			// when
+			err := config.Load(context.Background())
+			// then
+			assert.EqualError(t, err, testCase.expected)
+		})
+	}
+}
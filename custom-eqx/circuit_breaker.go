@@ -0,0 +1,157 @@
+package equinix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCircuitBreakerWindow   = time.Minute
+	defaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+var errCircuitOpen = errors.New("circuit breaker is open")
+
+type circuitBreakerBypassKey struct{}
+
+// WithCircuitBreakerBypass marks the requests sent with the returned context as
+// exempt from the circuit breaker, for critical reads such as credential validation.
+// Exempt requests are sent even when the circuit is open and their outcome is not
+// recorded, so they neither open nor close it.
+func WithCircuitBreakerBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, circuitBreakerBypassKey{}, true)
+}
+
+func bypassesCircuitBreaker(ctx context.Context) bool {
+	bypass, _ := ctx.Value(circuitBreakerBypassKey{}).(bool)
+	return bypass
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker stops requests to a failing service. After threshold consecutive
+// failures within the window the circuit opens and requests fail fast. Once the
+// cooldown passes, a single probe request is let through: the circuit closes when
+// it succeeds and opens again when it fails. Each change of state starts a new
+// generation, and the outcomes of the requests allowed in an earlier generation are
+// ignored, so that a request sent before the circuit opened cannot close it.
+type circuitBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu           sync.Mutex
+	state        circuitState
+	generation   uint64
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+}
+
+func newCircuitBreaker(threshold int, window, cooldown time.Duration, now func() time.Time) *circuitBreaker {
+	if window == 0 {
+		window = defaultCircuitBreakerWindow
+	}
+	if cooldown == 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &circuitBreaker{
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		now:       now,
+	}
+}
+
+// allow reports whether a request can be sent, along with the generation to record
+// its outcome with. In the half-open state only the first caller is allowed to probe
+// the service.
+func (b *circuitBreaker) allow() (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return 0, false
+		}
+		b.setState(circuitHalfOpen)
+		return b.generation, true
+	case circuitHalfOpen:
+		return 0, false
+	}
+	return b.generation, true
+}
+
+// record registers the outcome of a request allowed in the given generation. It is
+// ignored when the state changed since.
+func (b *circuitBreaker) record(generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return
+	}
+	now := b.now()
+	if success {
+		if b.state != circuitClosed {
+			b.setState(circuitClosed)
+		}
+		b.failures = 0
+		return
+	}
+	if b.state == circuitHalfOpen {
+		b.setState(circuitOpen)
+		b.openedAt = now
+		return
+	}
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.setState(circuitOpen)
+		b.openedAt = now
+		b.failures = 0
+	}
+}
+
+func (b *circuitBreaker) setState(state circuitState) {
+	b.state = state
+	b.generation++
+}
+
+// circuitBreakerTransport is a RoundTripper that fails fast while the circuit of
+// the service is open. Connection errors and server errors count as failures.
+// Requests whose context bypasses the circuit breaker are passed through.
+type circuitBreakerTransport struct {
+	service string
+	breaker *circuitBreaker
+	next    http.RoundTripper
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if bypassesCircuitBreaker(req.Context()) {
+		return t.next.RoundTrip(req)
+	}
+	generation, ok := t.breaker.allow()
+	if !ok {
+		return nil, fmt.Errorf("%s API: %w, not sending %s %s", t.service, errCircuitOpen, req.Method, req.URL.Path)
+	}
+	resp, err := t.next.RoundTrip(req)
+	t.breaker.record(generation, err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
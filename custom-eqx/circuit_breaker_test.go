@@ -0,0 +1,222 @@
+package equinix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestCircuitBreakerTransport(t *testing.T) {
+	// given
+	var healthy atomic.Value
+	healthy.Store(false)
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if !healthy.Load().(bool) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	clock := &fakeClock{now: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)}
+	config := Config{
+		CircuitBreakerThreshold: 3,
+		CircuitBreakerWindow:    time.Minute,
+		CircuitBreakerCooldown:  30 * time.Second,
+		now:                     clock.Now,
+	}
+	client := &http.Client{Transport: config.serviceTransport("ne", http.DefaultTransport)}
+	get := func() error {
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	// when
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, get(), "Requests are sent while circuit is closed")
+	}
+	errOpen := get()
+	// then
+	assert.True(t, errors.Is(errOpen, errCircuitOpen), "Circuit opens after threshold failures")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits), "Request is not sent while circuit is open")
+
+	// when cooldown passes and probe fails
+	clock.Advance(31 * time.Second)
+	assert.NoError(t, get(), "Probe request is sent after cooldown")
+	// then
+	assert.True(t, errors.Is(get(), errCircuitOpen), "Circuit opens again after failed probe")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&hits))
+
+	// when cooldown passes and probe succeeds
+	healthy.Store(true)
+	clock.Advance(31 * time.Second)
+	assert.NoError(t, get(), "Probe request is sent after cooldown")
+	// then
+	assert.NoError(t, get(), "Circuit closes after successful probe")
+	assert.Equal(t, int32(6), atomic.LoadInt32(&hits))
+}
+
+func TestCircuitBreakerTransport_bypass(t *testing.T) {
+	// given
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+	clock := &fakeClock{now: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)}
+	breaker := newCircuitBreaker(1, time.Minute, time.Minute, clock.Now)
+	recordOutcome(breaker, false)
+	client := &http.Client{Transport: &circuitBreakerTransport{service: "ne", breaker: breaker, next: http.DefaultTransport}}
+	send := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		assert.NoError(t, err)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	// when
+	errNormal := send(context.Background())
+	errExempt := send(WithCircuitBreakerBypass(context.Background()))
+	// then
+	assert.True(t, errors.Is(errNormal, errCircuitOpen), "Normal request is short-circuited")
+	assert.NoError(t, errExempt, "Exempt request is sent while circuit is open")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	assert.False(t, allowed(breaker), "Exempt request does not close the circuit")
+}
+
+func TestCircuitBreaker_window(t *testing.T) {
+	// given
+	clock := &fakeClock{now: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)}
+	breaker := newCircuitBreaker(2, time.Minute, time.Minute, clock.Now)
+	// when
+	recordOutcome(breaker, false)
+	clock.Advance(2 * time.Minute)
+	recordOutcome(breaker, false)
+	// then
+	assert.True(t, allowed(breaker), "Failures outside of window do not open the circuit")
+	// when
+	recordOutcome(breaker, false)
+	// then
+	assert.False(t, allowed(breaker), "Consecutive failures within window open the circuit")
+}
+
+func TestCircuitBreaker_staleOutcome(t *testing.T) {
+	// given
+	clock := &fakeClock{now: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)}
+	breaker := newCircuitBreaker(1, time.Minute, time.Minute, clock.Now)
+	slow, _ := breaker.allow()
+	recordOutcome(breaker, false)
+	clock.Advance(2 * time.Minute)
+	probe, ok := breaker.allow()
+	assert.True(t, ok, "Probe is allowed after the cooldown")
+	// when
+	breaker.record(slow, true)
+	// then
+	assert.False(t, allowed(breaker), "Success of a request sent before the circuit opened does not close it")
+	// when
+	breaker.record(probe, true)
+	// then
+	assert.True(t, allowed(breaker), "Success of the probe closes the circuit")
+	// when
+	breaker.record(slow, false)
+	breaker.record(probe, false)
+	// then
+	assert.True(t, allowed(breaker), "Failures of requests allowed in earlier states do not open the circuit")
+}
+
+// Records the outcome of a request allowed by the breaker.
+func recordOutcome(breaker *circuitBreaker, success bool) {
+	generation, _ := breaker.allow()
+	breaker.record(generation, success)
+}
+
+func allowed(breaker *circuitBreaker) bool {
+	_, ok := breaker.allow()
+	return ok
+}
+
+func TestConfig_serviceTransport_circuitBreakerDisabled(t *testing.T) {
+	// given
+	config := Config{}
+	// when
+	transport := config.serviceTransport("ne", http.DefaultTransport)
+	// then
+	_, ok := transport.(*circuitBreakerTransport)
+	assert.False(t, ok, "Circuit breaker is disabled by default")
+}
+
+func TestConfig_FabricToken_circuitBreakerBypass(t *testing.T) {
+	// given
+	var tokenRequests int32
+	var tokenAvailable atomic.Value
+	tokenAvailable.Store(false)
+	tokenServer := newTestTokenServer(t, oauthTokenPath, "fabric-token")
+	defer tokenServer.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != oauthTokenPath {
+			return
+		}
+		atomic.AddInt32(&tokenRequests, 1)
+		if !tokenAvailable.Load().(bool) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		tokenServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	config := Config{
+		BaseURL:                 server.URL,
+		ClientID:                "id",
+		ClientSecret:            "secret",
+		DeferFabricToken:        true,
+		CircuitBreakerThreshold: 1,
+	}
+	assert.NoError(t, config.Load(context.Background()))
+	client, err := config.ServiceHTTPClient("ne")
+	assert.NoError(t, err)
+	get := func() error {
+		resp, err := client.Get(server.URL + "/ne/v1/devices")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	// when
+	errFailed := get()
+	errShortCircuited := get()
+	// then
+	assert.Error(t, errFailed, "Request fails without a token")
+	assert.ErrorContains(t, errShortCircuited, errCircuitOpen.Error(), "Token refresh fails fast once the token endpoint failed")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests), "Token endpoint is not called while the circuit is open")
+	// when
+	tokenAvailable.Store(true)
+	token, err := config.FabricToken()
+	// then
+	assert.NoError(t, err, "Token is fetched while the circuit is open")
+	assert.Equal(t, "fabric-token", token)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&tokenRequests))
+	assert.NoError(t, get(), "Requests are authorized with the fetched token")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&tokenRequests), "Fetched token is reused")
+}
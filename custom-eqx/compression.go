@@ -0,0 +1,52 @@
+package equinix
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+)
+
+// gzipRequestTransport is a RoundTripper compressing POST and PUT request bodies
+// larger than the threshold, in bytes. Requests which already set a
+// Content-Encoding are sent as they are.
+type gzipRequestTransport struct {
+	threshold int
+	next      http.RoundTripper
+}
+
+func (t *gzipRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" ||
+		(req.Method != http.MethodPost && req.Method != http.MethodPut) {
+		return t.next.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	if len(body) <= t.threshold {
+		setRequestBody(req, body)
+		return t.next.RoundTrip(req)
+	}
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	setRequestBody(req, compressed.Bytes())
+	req.Header.Set("Content-Encoding", "gzip")
+	return t.next.RoundTrip(req)
+}
+
+func setRequestBody(req *http.Request, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+}
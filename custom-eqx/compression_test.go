@@ -0,0 +1,49 @@
+package equinix
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGzipRequestTransport(t *testing.T) {
+	// given
+	var encoding string
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		var body io.Reader = r.Body
+		if encoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			assert.NoError(t, err)
+			body = zr
+		}
+		received, _ = io.ReadAll(body)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &gzipRequestTransport{threshold: 16, next: http.DefaultTransport}}
+	large := `{"vendorConfiguration":"` + strings.Repeat("a", 64) + `"}`
+	// when
+	_, err := client.Post(server.URL, "application/json", strings.NewReader(large))
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "gzip", encoding, "Large body is compressed")
+	assert.Equal(t, large, string(received), "Body decodes server-side")
+	// when
+	_, err = client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, encoding, "Small body is not compressed")
+	assert.Equal(t, `{}`, string(received))
+	// when
+	req, _ := http.NewRequest(http.MethodPatch, server.URL, strings.NewReader(large))
+	_, err = client.Do(req)
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, encoding, "Only POST and PUT bodies are compressed")
+}
@@ -0,0 +1,53 @@
+package equinix
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// concurrencyLimiter is a semaphore bounding the number of API requests in flight
+// across all of the services sharing it.
+type concurrencyLimiter chan struct{}
+
+func newConcurrencyLimiter(max int) concurrencyLimiter {
+	return make(concurrencyLimiter, max)
+}
+
+// concurrencyLimitTransport is a RoundTripper holding a slot of the limiter for each
+// request, from before it is sent until its response body is closed. Requests wait
+// for a free slot until their context is done.
+type concurrencyLimitTransport struct {
+	limiter concurrencyLimiter
+	next    http.RoundTripper
+}
+
+func (t *concurrencyLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.limiter <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := func() { <-t.limiter }
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody releases the slot of its request once it is closed.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
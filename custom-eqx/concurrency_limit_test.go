@@ -0,0 +1,75 @@
+package equinix
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimitTransport(t *testing.T) {
+	// given
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	config := Config{BaseURL: DefaultBaseURL, Token: "token", MaxConcurrentRequests: 3}
+	if err := config.Load(context.Background()); err != nil {
+		t.Fatalf("Load returned error: %s", err)
+	}
+	clients := []*http.Client{
+		{Transport: config.serviceTransport("ne", http.DefaultTransport)},
+		{Transport: config.serviceTransport("metal", http.DefaultTransport)},
+	}
+	// when
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(client *http.Client) {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("request failed: %s", err)
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}(clients[i%len(clients)])
+	}
+	wg.Wait()
+	// then
+	assert.LessOrEqual(t, maxInFlight, int32(3), "Requests in flight across services never exceed the limit")
+	assert.Greater(t, maxInFlight, int32(1), "Requests are sent concurrently up to the limit")
+	assert.Len(t, config.concurrencyLimiter, 0, "All slots are released")
+}
+
+func TestConcurrencyLimitTransport_canceled(t *testing.T) {
+	// given
+	limiter := newConcurrencyLimiter(1)
+	limiter <- struct{}{}
+	transport := &concurrencyLimitTransport{limiter: limiter, next: http.DefaultTransport}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost", nil)
+	// when
+	resp, err := transport.RoundTrip(req)
+	// then
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Waiting for a slot stops once the context is done")
+}
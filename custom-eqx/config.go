@@ -1,9 +1,12 @@
 package equinix
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httputil"
@@ -11,13 +14,16 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
-	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
-	"github.com/equinix/ecx-go/v2"
 	"github.com/artraf/custom-ne-go"
-	"github.com/equinix/oauth2-go"
+	"github.com/artraf/equinix-custom-ne/custom-eqx/internal/datalist"
 	"github.com/artraf/equinix-custom-ne/version"
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/equinix/ecx-go/v2"
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/meta"
@@ -59,6 +65,14 @@ More information on the provider configuration can be found here:
 https://registry.terraform.io/providers/equinix/equinix/latest/docs`
 )
 
+const (
+	// DefaultPageSize is the number of records per page of paginated queries used
+	// when PageSize is not set
+	DefaultPageSize = 100
+	// MaxPageSize is the maximum number of records per page of paginated queries
+	MaxPageSize = 1000
+)
+
 var (
 	DefaultBaseURL   = "https://api.equinix.com"
 	DefaultTimeout   = 30
@@ -77,6 +91,141 @@ type Config struct {
 	RequestTimeout time.Duration
 	PageSize       int
 	Token          string
+	// MaxConcurrentRequests caps the number of API requests in flight at once across
+	// all of the services. Requests wait for others to complete beyond it. Zero means
+	// no limit
+	MaxConcurrentRequests int
+	// MaxResponseBytes is the maximum size, in bytes, of the API response bodies,
+	// beyond which reading them fails. Zero means no limit
+	MaxResponseBytes int64
+	// ServiceTimeouts override the RequestTimeout of the API clients of the services
+	// they are keyed by, one of "ecx", "ne" or "metal". See EffectiveTimeouts
+	ServiceTimeouts map[string]time.Duration
+	// ServiceTransports override the base transport of the API clients of the services
+	// they are keyed by, one of "ecx", "ne" or "metal", e.g. with a mock of a single
+	// service in tests. Authorization, User-Agent and logging are still layered over
+	// them, while the proxy, TLS and failover settings only apply to the default one
+	ServiceTransports map[string]http.RoundTripper
+	// ShareTransport makes the API clients use a base transport shared with the other
+	// Configs of the process with the same proxy, TLS and timeout settings, so that
+	// their connections are pooled together, e.g. for programs managing many
+	// accounts. Authorization stays specific to each Config
+	ShareTransport bool
+	// RetryableErrorSubstrings make the retry policy also retry requests whose
+	// connection error, or error response body, contains any of them, for transient
+	// errors that are only told apart by their message
+	RetryableErrorSubstrings []string
+	// RetryableNetErrors restricts the connection-level errors retried by the retry
+	// policy to the listed classes, among "connection_reset", "connection_refused",
+	// "unreachable", "dns", "timeout" and "eof". Other errors are then only retried
+	// when they contain any of the RetryableErrorSubstrings. All of them are retried
+	// when empty
+	RetryableNetErrors []string
+	// RetryReadsOnly restricts the retry policy to GET and HEAD requests, so that
+	// requests which may not be idempotent, e.g. POST, PUT or DELETE, are never
+	// retried, whatever their response status or error
+	RetryReadsOnly bool
+	// UserAgentComment is appended in parentheses to the provider product of the
+	// User-Agent of all API clients, e.g. to identify the pipeline running Terraform.
+	// It cannot contain control characters
+	UserAgentComment string
+	// MetalConsumerToken overrides the consumer token sent to Equinix Metal,
+	// which identifies the provider as the API consumer
+	MetalConsumerToken string
+	// TokenURL overrides the OAuth token endpoint, which defaults to the
+	// token path under BaseURL
+	TokenURL string
+	// Scopes requested with the OAuth token. The default scopes of the client are
+	// granted when empty
+	Scopes []string
+	// Audience is sent with the OAuth token request, for token endpoints requiring
+	// the intended audience of the token. It is omitted when empty
+	Audience string
+	// StrictDecoding logs a warning when the JSON documents decoded by the provider
+	// itself, rather than by the API client libraries, hold unknown fields, which may
+	// reveal API changes. The unknown fields are still ignored
+	StrictDecoding bool
+	// DeferFabricToken skips the OAuth token exchange performed by Load for the
+	// Fabric token, for configurations only using Network Edge. The token is then
+	// exchanged on the first API request, or by FabricToken
+	DeferFabricToken bool
+	// FallbackBaseURLs are tried in order when requests to the BaseURL fail at the
+	// connection level
+	FallbackBaseURLs []string
+	// Proxy is the URL of a proxy used for all requests. Hosts listed in the
+	// NO_PROXY environment variable bypass it
+	Proxy string
+	// ResponseHeaderTimeout limits the time spent waiting for the response headers,
+	// independently of the RequestTimeout. Zero means no limit
+	ResponseHeaderTimeout time.Duration
+	// DialTimeout limits the time spent establishing connections, independently
+	// of the RequestTimeout. Zero keeps the default of 30 seconds
+	DialTimeout time.Duration
+	// MinTLSVersion is the minimum TLS version accepted by all API clients, one of
+	// "1.0", "1.1", "1.2" or "1.3". The Go default applies when empty
+	MinTLSVersion string
+	// DisableHTTP2 makes all API clients use HTTP/1.1
+	DisableHTTP2 bool
+	// StrictNotFound makes resource reads fail on not found errors, instead of
+	// removing the resources from the state
+	StrictNotFound bool
+	// RateLimitWarningThreshold is the number of remaining requests of the API
+	// quota below which a warning is logged. Zero disables the warning
+	RateLimitWarningThreshold int
+	// AuthHeaderName is the header presenting the API token, Authorization by default
+	AuthHeaderName string
+	// AuthHeaderFormat formats the token in the auth header, with exactly one %s
+	// verb replaced by the token. Defaults to "Bearer %s"
+	AuthHeaderFormat string
+	// ExtraHeaders are added to every API request. Authorization and User-Agent
+	// headers cannot be overridden
+	ExtraHeaders map[string]string
+	// CircuitBreakerThreshold is the number of consecutive failures of a service,
+	// within CircuitBreakerWindow, after which its requests fail fast for the
+	// CircuitBreakerCooldown. Zero disables the circuit breaker. The token endpoint has
+	// a circuit of its own, which the token requests of Load, FabricToken and
+	// UpdateClientCredentials bypass
+	CircuitBreakerThreshold int
+	CircuitBreakerWindow    time.Duration
+	CircuitBreakerCooldown  time.Duration
+	// CredentialSource set to "keyring" loads the client secret or token from the
+	// OS keyring, under KeyringService and KeyringAccount names
+	CredentialSource string
+	KeyringService   string
+	KeyringAccount   string
+	// ProgressFunc, when set, is called after each page loaded by the data list data
+	// sources, e.g. eqx-custom-ne_network_devices, with the number of records fetched
+	// so far and the total number of records reported with the first page, which is
+	// negative when not known
+	ProgressFunc func(fetched, total int)
+	// MetricsSink, when set, receives the outcome and duration of every API request
+	MetricsSink MetricsSink
+	// AuditSink, when set, receives a record of every mutating API request,
+	// independently of debug logging
+	AuditSink AuditSink
+	// Backoff, when set, returns the delay before each retry of a request, given the
+	// number of the retry starting at 1, the minimum and maximum delays and the
+	// response of the failed attempt, which is nil for connection errors. The
+	// exponential backoff of the retry client is used otherwise
+	Backoff func(attempt int, min, max time.Duration, resp *http.Response) time.Duration
+	// RequestSigner, when set, is called with every API request right before it is
+	// sent, once its headers and body are final, e.g. to add HMAC signature headers.
+	// Requests failing to be signed are not sent
+	RequestSigner func(*http.Request) error
+	// RequestTracer, when set, traces every API request
+	RequestTracer RequestTracer
+	// ResponseCacheTTL is the duration for which successful GET responses are served
+	// from memory to identical requests. Zero disables the cache
+	ResponseCacheTTL time.Duration
+	// SlowRequestThreshold is the duration above which API requests are logged as
+	// slow. Zero disables the logging
+	SlowRequestThreshold time.Duration
+	// GzipRequestThreshold is the size, in bytes, above which POST and PUT request
+	// bodies are sent gzip compressed. Zero disables the compression, which must
+	// only be enabled for APIs accepting compressed requests
+	GzipRequestThreshold int
+
+	concurrencyLimiter concurrencyLimiter
 
 	ecx   ecx.Client
 	ne    ne.Client
@@ -88,62 +237,83 @@ type Config struct {
 
 	terraformVersion string
 	fabricClient     *v4.APIClient
-	FabricAuthToken  string
+	tokenSource      *rotatingTokenSource
+	serviceBase      http.RoundTripper
+	rateLimit        rateLimitState
+	deprecations     deprecationState
+	serviceStatesMu  sync.Mutex
+	serviceStates    map[string]*serviceState
+	now              func() time.Time
+	// Guards FabricAuthToken, which is replaced when the credentials are rotated
+	fabricTokenMu   sync.Mutex
+	FabricAuthToken string
 }
 
 // Load function validates configuration structure fields and configures
 // all required API clients.
 func (c *Config) Load(ctx context.Context) error {
-	if c.BaseURL == "" {
-		return fmt.Errorf("'baseURL' cannot be empty")
-	}
+	// A trailing slash would double the slash before the paths appended to the base
+	// URL, e.g. the token path
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
 
-	if c.Token == "" && (c.ClientID == "" || c.ClientSecret == "") && c.AuthToken == "" {
-		return fmt.Errorf(emptyCredentialsError)
+	if err := c.validate(); err != nil {
+		return err
 	}
 
-	var authClient *http.Client
-	if c.Token != "" {
-		tokenSource := xoauth2.StaticTokenSource(&xoauth2.Token{AccessToken: c.Token})
-		oauthTransport := &xoauth2.Transport{
-			Source: tokenSource,
+	if c.CredentialSource == credentialSourceKeyring {
+		if err := c.loadKeyringCredentials(); err != nil {
+			return err
 		}
-		authClient = &http.Client{
-			Transport: oauthTransport,
+	}
+
+	if c.MaxConcurrentRequests > 0 {
+		c.concurrencyLimiter = newConcurrencyLimiter(c.MaxConcurrentRequests)
+	}
+
+	transport, err := c.baseTransport()
+	if err != nil {
+		return err
+	}
+	var serviceBase http.RoundTripper = transport
+	if len(c.FallbackBaseURLs) > 0 {
+		if serviceBase, err = newFailoverTransport(c.BaseURL, c.FallbackBaseURLs, transport); err != nil {
+			return err
 		}
+	}
+
+	// The token is requested from the base URL by default, which fails over as well
+	tokenTransport := serviceBase
+	if c.MaxResponseBytes > 0 {
+		tokenTransport = &responseSizeLimitTransport{limit: c.MaxResponseBytes, next: serviceBase}
+	}
+	if c.CircuitBreakerThreshold > 0 {
+		tokenTransport = &circuitBreakerTransport{service: "auth", breaker: c.serviceState("auth").breaker, next: tokenTransport}
+	}
+	tokenHTTPClient := &http.Client{Transport: tokenTransport}
+	var tokenSource xoauth2.TokenSource
+	if c.Token != "" {
+		tokenSource = xoauth2.StaticTokenSource(&xoauth2.Token{AccessToken: c.Token})
 	} else {
-		authConfig := oauth2.Config{
-			ClientID:     c.ClientID,
-			ClientSecret: c.ClientSecret,
-			BaseURL:      c.BaseURL,
-		}
-		authClient = authConfig.New(ctx)
-
-		if c.ClientID != "" && c.ClientSecret != "" {
-			tke, err := authConfig.TokenSource(ctx, authClient).Token()
-			if err != nil {
-				if err != nil {
-					return err
-				}
-			}
-			if tke != nil {
-				c.FabricAuthToken = tke.AccessToken
+		var token *xoauth2.Token
+		if c.ClientID != "" && c.ClientSecret != "" && !c.DeferFabricToken {
+			if token, err = c.fetchClientCredentialsToken(ctx, tokenHTTPClient, c.ClientID, c.ClientSecret); err != nil {
+				return err
 			}
+			c.FabricAuthToken = token.AccessToken
 		}
+		tokenSource = c.clientCredentialsTokenSource(ctx, tokenHTTPClient, c.ClientID, c.ClientSecret, token)
 	}
 
 	if c.FabricAuthToken == "" {
 		c.FabricAuthToken = c.Token
 	}
-	authClient.Timeout = c.requestTimeout()
-	authClient.Transport = logging.NewTransport("Equinix", authClient.Transport)
-	ecxClient := ecx.NewClient(ctx, c.BaseURL, authClient)
-	neClient := ne.NewClient(ctx, c.BaseURL, authClient)
+	c.tokenSource = &rotatingTokenSource{ctx: ctx, client: tokenHTTPClient, source: tokenSource}
+	c.serviceBase = serviceBase
+	ecxClient := ecx.NewClient(ctx, c.BaseURL, c.newServiceHTTPClient("ecx", c.tokenSource, c.serviceBaseTransport("ecx")))
+	neClient := ne.NewClient(ctx, c.BaseURL, c.newServiceHTTPClient("ne", c.tokenSource, c.serviceBaseTransport("ne")))
 
-	if c.PageSize > 0 {
-		ecxClient.SetPageSize(c.PageSize)
-		neClient.SetPageSize(c.PageSize)
-	}
+	ecxClient.SetPageSize(c.pageSize())
+	neClient.SetPageSize(c.pageSize())
 	c.ecxUserAgent = c.fullUserAgent("equinix/ecx-go")
 	ecxClient.SetHeaders(map[string]string{
 		"User-agent": c.ecxUserAgent,
@@ -153,11 +323,219 @@ func (c *Config) Load(ctx context.Context) error {
 		"User-agent": c.neUserAgent,
 	})
 
+	metalClient, err := c.newMetalClient(c.serviceBaseTransport("metal"))
+	if err != nil {
+		return err
+	}
+	c.metalUserAgent = metalClient.UserAgent
+
 	c.ne = neClient
+	c.metal = metalClient
+	log.Printf("[DEBUG] Using User-Agents: %v", c.UserAgents())
 	return nil
 }
 
+// validate checks the configuration fields, without loading any credentials or
+// configuring API clients.
+func (c *Config) validate() error {
+	if c.BaseURL == "" {
+		return fmt.Errorf("'baseURL' cannot be empty")
+	}
+
+	switch c.CredentialSource {
+	case "":
+		if c.Token == "" && (c.ClientID == "" || c.ClientSecret == "") && c.AuthToken == "" {
+			return fmt.Errorf(emptyCredentialsError)
+		}
+	case credentialSourceKeyring:
+		// Missing credentials are read from the keyring by Load
+	default:
+		return fmt.Errorf("'credentialSource' must be one of: %q, got: %q", credentialSourceKeyring, c.CredentialSource)
+	}
+
+	if c.TokenURL != "" {
+		if u, err := url.Parse(c.TokenURL); err != nil || !u.IsAbs() || u.Host == "" {
+			return fmt.Errorf("'tokenURL' must be an absolute URL, got: %q", c.TokenURL)
+		}
+	}
+
+	if c.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("'maxConcurrentRequests' must not be negative, got: %d", c.MaxConcurrentRequests)
+	}
+
+	if c.MaxResponseBytes < 0 {
+		return fmt.Errorf("'maxResponseBytes' must not be negative, got: %d", c.MaxResponseBytes)
+	}
+
+	if c.PageSize < 0 || c.PageSize > MaxPageSize {
+		return fmt.Errorf("'pageSize' must be between 0 (default %d) and %d, got: %d", DefaultPageSize, MaxPageSize, c.PageSize)
+	}
+
+	if err := validateExtraHeaders(c.ExtraHeaders); err != nil {
+		return err
+	}
+
+	if err := c.validateAuthHeader(); err != nil {
+		return err
+	}
+
+	for _, scope := range c.Scopes {
+		if scope == "" || strings.ContainsAny(scope, " \t\r\n") {
+			return fmt.Errorf("'scopes' must be non-empty strings without whitespace, got: %q", scope)
+		}
+	}
+
+	for _, class := range c.RetryableNetErrors {
+		if !isNetErrorClass(class) {
+			return fmt.Errorf("'retryableNetErrors' must be one of: %s, got: %q", strings.Join(netErrorClasses, ", "), class)
+		}
+	}
 
+	for service, timeout := range c.ServiceTimeouts {
+		if !isAPIService(service) {
+			return fmt.Errorf("'serviceTimeouts' must be keyed by one of: %s, got: %q", strings.Join(apiServices, ", "), service)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("'serviceTimeouts' must not be negative, got: %s for %s", timeout, service)
+		}
+	}
+
+	for service, transport := range c.ServiceTransports {
+		if !isAPIService(service) {
+			return fmt.Errorf("'serviceTransports' must be keyed by one of: %s, got: %q", strings.Join(apiServices, ", "), service)
+		}
+		if transport == nil {
+			return fmt.Errorf("'serviceTransports' must not be nil, got: nil for %s", service)
+		}
+	}
+
+	if strings.IndexFunc(c.UserAgentComment, unicode.IsControl) >= 0 {
+		return fmt.Errorf("'userAgentComment' must not contain control characters, got: %q", c.UserAgentComment)
+	}
+
+	if strings.ContainsAny(c.Audience, " \t\r\n") {
+		return fmt.Errorf("'audience' must not contain whitespace, got: %q", c.Audience)
+	}
+	return nil
+}
+
+// FabricToken returns the Fabric token, exchanging it first when Load deferred it.
+// Concurrent callers wait for a single exchange.
+func (c *Config) FabricToken() (string, error) {
+	c.fabricTokenMu.Lock()
+	defer c.fabricTokenMu.Unlock()
+	if c.FabricAuthToken != "" {
+		return c.FabricAuthToken, nil
+	}
+	if c.tokenSource == nil {
+		return "", fmt.Errorf("the Fabric token cannot be fetched before the configuration is loaded")
+	}
+	tke, err := c.fetchClientCredentialsToken(c.tokenSource.ctx, c.tokenSource.client, c.ClientID, c.ClientSecret)
+	if err != nil {
+		return "", err
+	}
+	c.tokenSource.set(c.clientCredentialsTokenSource(c.tokenSource.ctx, c.tokenSource.client, c.ClientID, c.ClientSecret, tke))
+	c.FabricAuthToken = tke.AccessToken
+	return c.FabricAuthToken, nil
+}
+
+// newMetalClient creates the Equinix Metal client. Requests failing at the
+// connection level are retried according to the MetalRetryPolicy.
+func (c *Config) newMetalClient(base http.RoundTripper) (*packngo.Client, error) {
+	metalHTTPClient := c.newMetalHTTPClient(base)
+	metalURL := strings.TrimSuffix(c.BaseURL, "/") + metalBasePath
+	client, err := packngo.NewClientWithBaseURL(c.metalConsumerToken(), c.AuthToken, metalHTTPClient.StandardClient(), metalURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Equinix Metal client: %s", err)
+	}
+	client.UserAgent = c.fullUserAgent(client.UserAgent)
+	return client, nil
+}
+
+func (c *Config) newMetalHTTPClient(base http.RoundTripper) *retryablehttp.Client {
+	metalHTTPClient := retryablehttp.NewClient()
+	metalHTTPClient.HTTPClient.Transport = logging.NewTransport("Equinix Metal", c.serviceTransport("metal", base))
+	metalHTTPClient.HTTPClient.Timeout = c.serviceRequestTimeout("metal")
+	metalHTTPClient.RetryMax = c.MaxRetries
+	metalHTTPClient.RetryWaitMin = time.Second
+	metalHTTPClient.RetryWaitMax = c.MaxRetryWait
+	metalHTTPClient.CheckRetry = c.metalRetryPolicy
+	if c.Backoff != nil {
+		metalHTTPClient.Backoff = func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+			return c.Backoff(attemptNum+1, min, max, resp)
+		}
+	}
+	metalHTTPClient.Logger = nil
+	return metalHTTPClient
+}
+
+func (c *Config) metalConsumerToken() string {
+	if c.MetalConsumerToken != "" {
+		return c.MetalConsumerToken
+	}
+	return consumerToken
+}
+
+// UserAgents returns User-Agent strings, keyed by service name, that are sent
+// with the API requests. Services that were not configured are omitted.
+func (c *Config) UserAgents() map[string]string {
+	userAgents := make(map[string]string)
+	for service, ua := range map[string]string{
+		"ecx":   c.ecxUserAgent,
+		"ne":    c.neUserAgent,
+		"metal": c.metalUserAgent,
+	} {
+		if ua != "" {
+			userAgents[service] = ua
+		}
+	}
+	return userAgents
+}
+
+// clientCredentialsTokenSource returns the token source of the client credentials,
+// starting with the given token, which may be nil.
+func (c *Config) clientCredentialsTokenSource(ctx context.Context, hc *http.Client, clientID, clientSecret string, token *xoauth2.Token) xoauth2.TokenSource {
+	authConfig := clientCredentialsConfig{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       c.tokenURL(),
+		Scopes:         c.Scopes,
+		Audience:       c.Audience,
+		StrictDecoding: c.StrictDecoding,
+	}
+	return authConfig.TokenSource(ctx, hc, token)
+}
+
+// fetchClientCredentialsToken fetches a token with the client credentials, e.g. to
+// validate them. The token request bypasses the circuit breaker of the token
+// endpoint, so that it is attempted even after the token refreshes of the API
+// requests kept failing.
+func (c *Config) fetchClientCredentialsToken(ctx context.Context, hc *http.Client, clientID, clientSecret string) (*xoauth2.Token, error) {
+	return c.clientCredentialsTokenSource(WithCircuitBreakerBypass(ctx), hc, clientID, clientSecret, nil).Token()
+}
+
+func (c *Config) tokenURL() string {
+	if c.TokenURL != "" {
+		return c.TokenURL
+	}
+	return c.BaseURL + oauthTokenPath
+}
+
+// dataListProgress returns the ProgressFunc of the provider configuration given as
+// meta, for the data list data sources loading records page by page.
+func dataListProgress(meta interface{}) datalist.ProgressFunc {
+	if c, ok := meta.(*Config); ok && c.ProgressFunc != nil {
+		return c.ProgressFunc
+	}
+	return nil
+}
+
+func (c *Config) pageSize() int {
+	if c.PageSize == 0 {
+		return DefaultPageSize
+	}
+	return c.PageSize
+}
 
 func (c *Config) requestTimeout() time.Duration {
 	if c.RequestTimeout == 0 {
@@ -166,6 +544,46 @@ func (c *Config) requestTimeout() time.Duration {
 	return c.RequestTimeout
 }
 
+// The API services, which name the clients configured by Load.
+var apiServices = []string{"ecx", "ne", "metal"}
+
+func isAPIService(service string) bool {
+	for _, s := range apiServices {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the request timeout of the client of the service: its override in
+// ServiceTimeouts, if set, or the RequestTimeout.
+func (c *Config) serviceRequestTimeout(service string) time.Duration {
+	if timeout := c.ServiceTimeouts[service]; timeout > 0 {
+		return timeout
+	}
+	return c.requestTimeout()
+}
+
+// Returns the base transport of the client of the service: its override in
+// ServiceTransports, or the transport shared by all of the services.
+func (c *Config) serviceBaseTransport(service string) http.RoundTripper {
+	if transport := c.ServiceTransports[service]; transport != nil {
+		return transport
+	}
+	return c.serviceBase
+}
+
+// EffectiveTimeouts returns the request timeout applied to the client of each API
+// service, once ServiceTimeouts overrides and defaults are resolved.
+func (c *Config) EffectiveTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration, len(apiServices))
+	for _, service := range apiServices {
+		timeouts[service] = c.serviceRequestTimeout(service)
+	}
+	return timeouts
+}
+
 func MetalRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
 	if ctx.Err() != nil {
 		return false, ctx.Err()
@@ -189,6 +607,66 @@ func MetalRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool
 	return false, nil
 }
 
+// The size of the error response bodies searched for the RetryableErrorSubstrings.
+const maxRetryableErrorBodySize = 64 * 1024
+
+// metalRetryPolicy retries the requests retried by the MetalRetryPolicy, limited to
+// the RetryableNetErrors for connection-level errors, as well as those failing with
+// any of the RetryableErrorSubstrings. Only GET and HEAD requests are retried with
+// RetryReadsOnly.
+func (c *Config) metalRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if c.RetryReadsOnly && !isReadRequest(resp, err) {
+		return false, ctx.Err()
+	}
+	retry, policyErr := MetalRetryPolicy(ctx, resp, err)
+	if retry && err != nil {
+		retry = c.isRetryableNetError(err)
+	}
+	if retry || policyErr != nil || len(c.RetryableErrorSubstrings) == 0 {
+		return retry, policyErr
+	}
+	if err != nil {
+		return c.isRetryableErrorMessage(err.Error()), nil
+	}
+	if resp == nil || resp.StatusCode < http.StatusBadRequest || resp.Body == nil {
+		return false, nil
+	}
+	// The body is restored, as it is read again to report the error when it
+	// is not retried
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxRetryableErrorBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if readErr != nil {
+		return false, nil
+	}
+	return c.isRetryableErrorMessage(string(body)), nil
+}
+
+// Reports whether the request of the response, or of the error when it failed at the
+// connection level, is a GET or HEAD request. Requests of unknown method are not.
+func isReadRequest(resp *http.Response, err error) bool {
+	var method string
+	var urlErr *url.Error
+	if resp != nil && resp.Request != nil {
+		method = resp.Request.Method
+	} else if errors.As(err, &urlErr) {
+		// The operation of the errors returned by http.Client is the request method
+		method = strings.ToUpper(urlErr.Op)
+	}
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func (c *Config) isRetryableErrorMessage(message string) bool {
+	for _, substring := range c.RetryableErrorSubstrings {
+		if substring != "" && strings.Contains(message, substring) {
+			return true
+		}
+	}
+	return false
+}
+
 func terraformUserAgent(version string) string {
 	ua := fmt.Sprintf("HashiCorp Terraform/%s (+https://www.terraform.io) Terraform Plugin SDK/%s",
 		version, meta.SDKVersionString())
@@ -213,7 +691,10 @@ func (c *Config) addModuleToECXUserAgent(client *ecx.Client, d *schema.ResourceD
 
 func (c *Config) addModuleToNEUserAgent(client *ne.Client, d *schema.ResourceData) {
 	cli := *client
-	rc := cli.(*ne.RestClient)
+	rc, ok := cli.(*ne.RestClient)
+	if !ok {
+		return
+	}
 	rc.SetHeader("User-agent", generateModuleUserAgentString(d, c.neUserAgent))
 	*client = rc
 }
@@ -234,6 +715,10 @@ func generateModuleUserAgentString(d *schema.ResourceData, baseUserAgent string)
 
 func (c *Config) fullUserAgent(suffix string) string {
 	tfUserAgent := terraformUserAgent(c.terraformVersion)
-	userAgent := fmt.Sprintf("%s terraform-provider-equinix/%s %s", tfUserAgent, version.ProviderVersion, suffix)
+	product := fmt.Sprintf("terraform-provider-equinix/%s", version.ProviderVersion)
+	if comment := strings.TrimSpace(c.UserAgentComment); comment != "" {
+		product = fmt.Sprintf("%s (%s)", product, comment)
+	}
+	userAgent := fmt.Sprintf("%s %s %s", tfUserAgent, product, suffix)
 	return strings.TrimSpace(userAgent)
 }
@@ -0,0 +1,87 @@
+package equinix
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ConfigFromMap returns the configuration of the provider arguments held in the map,
+// keyed like in the provider configuration block, for tools embedding the API clients
+// without Terraform. Missing arguments take their provider defaults, including the ones
+// read from environment variables. The arguments are validated as by the provider and
+// by Load, which must still be called to configure the API clients.
+func ConfigFromMap(m map[string]interface{}) (*Config, error) {
+	providerSchema := Provider().Schema
+	for key := range m {
+		if _, ok := providerSchema[key]; !ok {
+			return nil, fmt.Errorf("unsupported provider argument %q, supported arguments are: %v", key, providerArguments(providerSchema))
+		}
+	}
+
+	values := make(map[string]interface{}, len(providerSchema))
+	for key, s := range providerSchema {
+		value, ok := m[key]
+		if !ok {
+			defaultValue, err := s.DefaultValue()
+			if err != nil {
+				return nil, fmt.Errorf("unable to read the default of provider argument %q: %s", key, err)
+			}
+			if defaultValue == nil {
+				defaultValue = s.ZeroValue()
+			}
+			// Defaults read from environment variables are strings
+			if str, ok := defaultValue.(string); ok && s.Type == schema.TypeInt {
+				if defaultValue, err = strconv.Atoi(str); err != nil {
+					return nil, fmt.Errorf("provider argument %q must be an integer, got: %q", key, str)
+				}
+			}
+			values[key] = defaultValue
+			continue
+		}
+		if err := checkProviderArgumentType(key, s, value); err != nil {
+			return nil, err
+		}
+		if s.ValidateFunc != nil {
+			if _, errs := s.ValidateFunc(value, key); len(errs) > 0 {
+				return nil, errs[0]
+			}
+		}
+		values[key] = value
+	}
+
+	config := newProviderConfig(func(key string) interface{} {
+		return values[key]
+	})
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func checkProviderArgumentType(key string, s *schema.Schema, value interface{}) error {
+	ok := false
+	switch s.Type {
+	case schema.TypeString:
+		_, ok = value.(string)
+	case schema.TypeInt:
+		_, ok = value.(int)
+	case schema.TypeBool:
+		_, ok = value.(bool)
+	}
+	if !ok {
+		return fmt.Errorf("provider argument %q must be of type %s, got: %T", key, s.Type, value)
+	}
+	return nil
+}
+
+func providerArguments(providerSchema map[string]*schema.Schema) []string {
+	arguments := make([]string, 0, len(providerSchema))
+	for key := range providerSchema {
+		arguments = append(arguments, key)
+	}
+	sort.Strings(arguments)
+	return arguments
+}
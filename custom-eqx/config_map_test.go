@@ -0,0 +1,58 @@
+package equinix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromMap(t *testing.T) {
+	// given
+	t.Setenv(endpointEnvVar, "")
+	t.Setenv(credentialSourceEnvVar, "")
+	t.Setenv(clientTimeoutEnvVar, "45")
+	m := map[string]interface{}{
+		"client_id":              "id",
+		"client_secret":          "secret",
+		"response_max_page_size": 500,
+		"max_retries":            3,
+	}
+	// when
+	config, err := ConfigFromMap(m)
+	// then
+	assert.NoError(t, err, "ConfigFromMap does not return an error")
+	assert.Equal(t, DefaultBaseURL, config.BaseURL, "Missing arguments take their defaults")
+	assert.Equal(t, "id", config.ClientID)
+	assert.Equal(t, "secret", config.ClientSecret)
+	assert.Equal(t, 500, config.PageSize)
+	assert.Equal(t, 3, config.MaxRetries)
+	assert.Equal(t, 30*time.Second, config.MaxRetryWait)
+	assert.Equal(t, 45*time.Second, config.RequestTimeout, "Defaults are read from environment variables")
+	assert.Equal(t, defaultKeyringService, config.KeyringService)
+}
+
+func TestConfigFromMap_invalid(t *testing.T) {
+	for _, envVar := range []string{clientSecretEnvVar, clientTokenEnvVar, metalAuthTokenEnvVar, credentialSourceEnvVar} {
+		t.Setenv(envVar, "")
+	}
+	testCases := []struct {
+		m        map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"token": "token", "endpoint": 443}, `"endpoint" must be of type TypeString`},
+		{map[string]interface{}{"token": "token", "unknown": "value"}, `unsupported provider argument "unknown"`},
+		{map[string]interface{}{"token": "token", "request_timeout": 0}, "request_timeout"},
+		{map[string]interface{}{"token": "token", "credential_source": "vault"}, "credential_source"},
+		{map[string]interface{}{"client_id": "id"}, emptyCredentialsError},
+	}
+	for _, testCase := range testCases {
+		// when
+		config, err := ConfigFromMap(testCase.m)
+		// then
+		assert.Nil(t, config, "No configuration is returned for %v", testCase.m)
+		if assert.Error(t, err, "ConfigFromMap returns an error for %v", testCase.m) {
+			assert.Contains(t, err.Error(), testCase.expected)
+		}
+	}
+}
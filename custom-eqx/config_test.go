@@ -0,0 +1,678 @@
+package equinix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/artraf/custom-ne-go"
+	"github.com/artraf/equinix-custom-ne/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTokenServer(t *testing.T, tokenPath string, accessToken string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(tokenPath, func(w http.ResponseWriter, r *http.Request) {
+		req := clientCredentialsTokenRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("unable to decode token request: %s", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(clientCredentialsTokenResponse{
+			AccessToken:  accessToken,
+			TokenTimeout: "3600",
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestConfig_Load_customTokenURL(t *testing.T) {
+	// given
+	server := newTestTokenServer(t, "/custom/token", "custom-token")
+	defer server.Close()
+	config := Config{
+		BaseURL:      server.URL,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     server.URL + "/custom/token",
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.NoError(t, err, "Load does not return an error")
+	assert.Equal(t, "custom-token", config.FabricAuthToken, "Token is fetched from custom endpoint")
+}
+
+func TestConfig_Load_defaultTokenURL(t *testing.T) {
+	// given
+	server := newTestTokenServer(t, oauthTokenPath, "default-token")
+	defer server.Close()
+	config := Config{
+		BaseURL:      server.URL,
+		ClientID:     "id",
+		ClientSecret: "secret",
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.NoError(t, err, "Load does not return an error")
+	assert.Equal(t, "default-token", config.FabricAuthToken, "Token is fetched from default endpoint")
+}
+
+func TestConfig_Load_trailingSlashBaseURL(t *testing.T) {
+	// given
+	var tokenPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenPaths = append(tokenPaths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(clientCredentialsTokenResponse{
+			AccessToken:  "token",
+			TokenTimeout: "3600",
+		})
+	}))
+	defer server.Close()
+	config := Config{
+		BaseURL:      server.URL + "//",
+		ClientID:     "id",
+		ClientSecret: "secret",
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.NoError(t, err, "Load does not return an error")
+	assert.Equal(t, server.URL, config.BaseURL, "Trailing slashes are trimmed from the base URL")
+	assert.Equal(t, server.URL+oauthTokenPath, config.tokenURL(), "Token URL has a single slash before the token path")
+	assert.Equal(t, []string{oauthTokenPath}, tokenPaths, "Token is requested from the token path")
+	assert.Equal(t, "token", config.FabricAuthToken)
+}
+
+func TestConfig_Load_slashBaseURL(t *testing.T) {
+	// given
+	config := Config{BaseURL: "/", Token: "token"}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Error(t, err, "Base URL made of slashes is empty")
+}
+
+func TestConfig_Load_deferFabricToken(t *testing.T) {
+	// given
+	var tokenRequests int32
+	tokenServer := newTestTokenServer(t, oauthTokenPath, "deferred-token")
+	defer tokenServer.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == oauthTokenPath {
+			atomic.AddInt32(&tokenRequests, 1)
+		}
+		tokenServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	config := Config{
+		BaseURL:          server.URL,
+		ClientID:         "id",
+		ClientSecret:     "secret",
+		DeferFabricToken: true,
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.NoError(t, err, "Load does not return an error")
+	assert.NotNil(t, config.ne, "Network Edge client is created")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&tokenRequests), "Token is not fetched by Load")
+	assert.Empty(t, config.FabricAuthToken)
+	// when
+	token, err := config.FabricToken()
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "deferred-token", token, "Token is fetched on demand")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests))
+}
+
+func TestConfig_FabricToken_concurrent(t *testing.T) {
+	// given
+	var tokenRequests int32
+	tokenServer := newTestTokenServer(t, oauthTokenPath, "deferred-token")
+	defer tokenServer.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		tokenServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	config := Config{
+		BaseURL:          server.URL,
+		ClientID:         "id",
+		ClientSecret:     "secret",
+		DeferFabricToken: true,
+	}
+	assert.NoError(t, config.Load(context.Background()))
+	// when
+	tokens := make([]string, 8)
+	var wg sync.WaitGroup
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = config.FabricToken()
+		}(i)
+	}
+	wg.Wait()
+	// then
+	for _, token := range tokens {
+		assert.Equal(t, "deferred-token", token)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests), "Token is exchanged once")
+}
+
+func TestConfig_Load_invalidTokenURL(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:      DefaultBaseURL,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     "/oauth2/v1/token",
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Error(t, err, "Load returns an error for relative token URL")
+}
+
+func TestConfig_UserAgents(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:          DefaultBaseURL,
+		Token:            "token",
+		terraformVersion: "1.3.0",
+	}
+	// when
+	err := config.Load(context.Background())
+	userAgents := config.UserAgents()
+	// then
+	assert.NoError(t, err, "Load does not return an error")
+	assert.Len(t, userAgents, 3, "User-Agents of configured services are returned")
+	assert.True(t, strings.HasSuffix(userAgents["ecx"], "equinix/ecx-go"), "ECX User-Agent has expected suffix")
+	assert.True(t, strings.HasSuffix(userAgents["ne"], "equinix/ecx-go"), "NE User-Agent has expected suffix")
+	assert.True(t, strings.Contains(userAgents["metal"], "packngo/"), "Metal User-Agent contains packngo version")
+	assert.Contains(t, userAgents["ne"], "HashiCorp Terraform/1.3.0", "NE User-Agent contains Terraform version")
+}
+
+func TestConfig_UserAgents_comment(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:          DefaultBaseURL,
+		Token:            "token",
+		UserAgentComment: "pipeline 1234",
+	}
+	// when
+	err := config.Load(context.Background())
+	userAgents := config.UserAgents()
+	// then
+	assert.NoError(t, err, "Load does not return an error")
+	for service, userAgent := range userAgents {
+		assert.Contains(t, userAgent, "terraform-provider-equinix/"+version.ProviderVersion+" (pipeline 1234) ", "%s User-Agent contains the comment", service)
+	}
+	assert.True(t, strings.HasSuffix(userAgents["ne"], "equinix/ecx-go"), "NE User-Agent keeps its suffix")
+}
+
+func TestConfig_Load_invalidUserAgentComment(t *testing.T) {
+	for _, comment := range []string{"pipeline\r\nX-Injected: 1", "tab\tseparated", "bell\a"} {
+		// given
+		config := Config{
+			BaseURL:          DefaultBaseURL,
+			Token:            "token",
+			UserAgentComment: comment,
+		}
+		// when
+		err := config.Load(context.Background())
+		// then
+		assert.Error(t, err, "Load returns an error for %q", comment)
+		assert.Contains(t, err.Error(), "'userAgentComment'")
+	}
+}
+
+func TestConfig_Load_scopes(t *testing.T) {
+	// given
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := clientCredentialsTokenRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("unable to decode token request: %s", err)
+		}
+		requested = append(requested, req.Scope)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(clientCredentialsTokenResponse{AccessToken: "token"})
+	}))
+	defer server.Close()
+	for _, scopes := range [][]string{{"ne:read", "ne:write"}, nil} {
+		config := Config{
+			BaseURL:      server.URL,
+			TokenURL:     server.URL + "/token",
+			ClientID:     "id",
+			ClientSecret: "secret",
+			Scopes:       scopes,
+		}
+		// when
+		err := config.Load(context.Background())
+		// then
+		assert.NoError(t, err, "Load does not return an error")
+	}
+	assert.Equal(t, []string{"ne:read ne:write", ""}, requested, "Scopes are requested only when configured")
+}
+
+func TestConfig_Load_invalidScopes(t *testing.T) {
+	for _, scope := range []string{"", "ne:read ne:write"} {
+		// given
+		config := Config{
+			BaseURL:      DefaultBaseURL,
+			ClientID:     "id",
+			ClientSecret: "secret",
+			Scopes:       []string{"ne:read", scope},
+		}
+		// when
+		err := config.Load(context.Background())
+		// then
+		assert.Error(t, err, "Load returns an error for scope %q", scope)
+		assert.Contains(t, err.Error(), "'scopes'")
+	}
+}
+
+func TestConfig_Load_audience(t *testing.T) {
+	// given
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("unable to decode token request: %s", err)
+		}
+		audience, ok := req["audience"].(string)
+		if !ok {
+			audience = "<none>"
+		}
+		requested = append(requested, audience)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(clientCredentialsTokenResponse{AccessToken: "token"})
+	}))
+	defer server.Close()
+	for _, audience := range []string{"https://api.equinix.com", ""} {
+		config := Config{
+			BaseURL:      server.URL,
+			TokenURL:     server.URL + "/token",
+			ClientID:     "id",
+			ClientSecret: "secret",
+			Audience:     audience,
+		}
+		// when
+		err := config.Load(context.Background())
+		// then
+		assert.NoError(t, err, "Load does not return an error")
+	}
+	assert.Equal(t, []string{"https://api.equinix.com", "<none>"}, requested, "Audience is requested only when configured")
+}
+
+func TestConfig_Load_strictDecoding(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "token", "token_timeout": "3600", "scope": "ne"}`))
+	}))
+	defer server.Close()
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	config := Config{
+		BaseURL:        server.URL,
+		TokenURL:       server.URL + "/token",
+		ClientID:       "id",
+		ClientSecret:   "secret",
+		StrictDecoding: true,
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.NoError(t, err, "Load does not return an error")
+	assert.Equal(t, "token", config.FabricAuthToken)
+	assert.Contains(t, logs.String(), `[WARN] Unexpected token response: unknown field "scope"`)
+}
+
+func TestConfig_Load_invalidAudience(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:      DefaultBaseURL,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Audience:     "https://api.equinix.com other",
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Error(t, err, "Load returns an error")
+	assert.Contains(t, err.Error(), "'audience'")
+}
+
+func TestConfig_EffectiveTimeouts(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:         DefaultBaseURL,
+		Token:           "token",
+		RequestTimeout:  20 * time.Second,
+		ServiceTimeouts: map[string]time.Duration{"metal": time.Minute},
+	}
+	defaultConfig := Config{}
+	// when
+	err := config.Load(context.Background())
+	timeouts := config.EffectiveTimeouts()
+	// then
+	assert.NoError(t, err, "Load does not return an error")
+	assert.Equal(t, map[string]time.Duration{"ecx": 20 * time.Second, "ne": 20 * time.Second, "metal": time.Minute}, timeouts, "Service timeouts override the request timeout")
+	assert.Equal(t, time.Minute, config.newMetalHTTPClient(http.DefaultTransport).HTTPClient.Timeout, "Metal client applies its effective timeout")
+	assert.Equal(t, 20*time.Second, config.newServiceHTTPClient("ne", config.tokenSource, http.DefaultTransport).Timeout, "NE client applies its effective timeout")
+	assert.Equal(t, map[string]time.Duration{"ecx": 5 * time.Second, "ne": 5 * time.Second, "metal": 5 * time.Second}, defaultConfig.EffectiveTimeouts(), "Default request timeout applies without overrides")
+}
+
+func TestConfig_Load_invalidServiceTimeouts(t *testing.T) {
+	for _, timeouts := range []map[string]time.Duration{{"fabric": time.Minute}, {"ne": -time.Second}} {
+		// given
+		config := Config{
+			BaseURL:         DefaultBaseURL,
+			Token:           "token",
+			ServiceTimeouts: timeouts,
+		}
+		// when
+		err := config.Load(context.Background())
+		// then
+		assert.Error(t, err, "Load returns an error for %v", timeouts)
+		assert.Contains(t, err.Error(), "'serviceTimeouts'")
+	}
+}
+
+type stubServiceTransport struct {
+	requests []*http.Request
+}
+
+func (t *stubServiceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`[]`)),
+		Request:    req,
+	}, nil
+}
+
+func TestConfig_Load_serviceTransports(t *testing.T) {
+	// given
+	var metalRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&metalRequests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"projects": []}`))
+	}))
+	defer server.Close()
+	neTransport := &stubServiceTransport{}
+	config := Config{
+		BaseURL:           server.URL,
+		Token:             "token",
+		AuthToken:         "auth-token",
+		ServiceTransports: map[string]http.RoundTripper{"ne": neTransport},
+	}
+	assert.NoError(t, config.Load(context.Background()))
+	// when
+	_, neErr := config.ne.GetSSHPublicKeys()
+	_, _, metalErr := config.metal.Projects.List(nil)
+	// then
+	assert.NoError(t, neErr)
+	assert.NoError(t, metalErr)
+	assert.Len(t, neTransport.requests, 1, "NE requests are sent over its designated transport")
+	assert.Equal(t, "Bearer token", neTransport.requests[0].Header.Get("Authorization"), "Authorization is layered over the designated transport")
+	assert.Contains(t, neTransport.requests[0].Header.Get("User-Agent"), "equinix/ecx-go", "User-Agent is layered over the designated transport")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&metalRequests), "Metal requests are sent over the default transport")
+}
+
+func TestConfig_Load_invalidServiceTransports(t *testing.T) {
+	for _, transports := range []map[string]http.RoundTripper{{"fabric": http.DefaultTransport}, {"ne": nil}} {
+		// given
+		config := Config{
+			BaseURL:           DefaultBaseURL,
+			Token:             "token",
+			ServiceTransports: transports,
+		}
+		// when
+		err := config.Load(context.Background())
+		// then
+		assert.Error(t, err, "Load returns an error for %v", transports)
+		assert.Contains(t, err.Error(), "'serviceTransports'")
+	}
+}
+
+func TestConfig_Load_metalConsumerToken(t *testing.T) {
+	for _, customToken := range []string{"custom-consumer-token", ""} {
+		// given
+		var received string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Get("X-Consumer-Token")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"projects":[]}`))
+		}))
+		config := Config{
+			BaseURL:            server.URL,
+			AuthToken:          "auth-token",
+			MetalConsumerToken: customToken,
+		}
+		// when
+		err := config.Load(context.Background())
+		assert.NoError(t, err, "Load does not return an error")
+		_, _, err = config.metal.Projects.List(nil)
+		server.Close()
+		// then
+		assert.NoError(t, err)
+		expected := customToken
+		if expected == "" {
+			expected = consumerToken
+		}
+		assert.Equal(t, expected, received, "Consumer token is sent with Metal requests")
+	}
+}
+
+func TestConfig_newMetalHTTPClient_backoff(t *testing.T) {
+	// given
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", strconv.Itoa(requests*10))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	var attempts []int
+	var retryAfters []string
+	config := Config{
+		MaxRetries:   3,
+		MaxRetryWait: time.Minute,
+		Backoff: func(attempt int, min, max time.Duration, resp *http.Response) time.Duration {
+			attempts = append(attempts, attempt)
+			retryAfters = append(retryAfters, resp.Header.Get("Retry-After"))
+			return time.Millisecond
+		},
+	}
+	client := config.newMetalHTTPClient(http.DefaultTransport)
+	client.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		return resp.StatusCode == http.StatusServiceUnavailable, nil
+	}
+	// when
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []int{1, 2}, attempts, "Backoff is consulted before each retry")
+	assert.Equal(t, []string{"10", "20"}, retryAfters, "Backoff receives the failed response")
+	assert.Less(t, time.Since(start), time.Second, "Delays returned by the backoff are used")
+}
+
+func TestConfig_newMetalHTTPClient_retryableErrorSubstrings(t *testing.T) {
+	testCases := []struct {
+		name             string
+		substrings       []string
+		expectedRequests int
+		expectedStatus   int
+	}{
+		{"Matching", []string{"no such host", "temporarily unavailable"}, 3, http.StatusOK},
+		{"NotMatching", []string{"no such host"}, 1, http.StatusInternalServerError},
+		{"None", nil, 1, http.StatusInternalServerError},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests < 3 {
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write([]byte(`{"errorMessage": "Service temporarily unavailable"}`))
+				}
+			}))
+			defer server.Close()
+			config := Config{
+				MaxRetries:               3,
+				MaxRetryWait:             time.Minute,
+				RetryableErrorSubstrings: testCase.substrings,
+				Backoff: func(attempt int, min, max time.Duration, resp *http.Response) time.Duration {
+					return time.Millisecond
+				},
+			}
+			client := config.newMetalHTTPClient(http.DefaultTransport)
+			// when
+			resp, err := client.Get(server.URL)
+			// then
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+			assert.Equal(t, testCase.expectedRequests, requests)
+			if testCase.expectedStatus != http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, `{"errorMessage": "Service temporarily unavailable"}`, string(body), "Error response body is preserved")
+			}
+		})
+	}
+}
+
+func TestConfig_newMetalHTTPClient_retryReadsOnly(t *testing.T) {
+	testCases := []struct {
+		method           string
+		retryReadsOnly   bool
+		expectedRequests int32
+	}{
+		{http.MethodGet, true, 3},
+		{http.MethodPost, true, 1},
+		{http.MethodDelete, true, 1},
+		{http.MethodPost, false, 3},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("%s/%t", testCase.method, testCase.retryReadsOnly), func(t *testing.T) {
+			// given
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"errorMessage": "Service temporarily unavailable"}`))
+			}))
+			defer server.Close()
+			config := Config{
+				MaxRetries:               2,
+				RetryReadsOnly:           testCase.retryReadsOnly,
+				RetryableErrorSubstrings: []string{"temporarily unavailable"},
+				Backoff: func(attempt int, min, max time.Duration, resp *http.Response) time.Duration {
+					return time.Millisecond
+				},
+			}
+			client := config.newMetalHTTPClient(http.DefaultTransport).StandardClient()
+			req, err := http.NewRequest(testCase.method, server.URL, nil)
+			assert.NoError(t, err)
+			// when
+			resp, err := client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			// then
+			assert.Equal(t, testCase.expectedRequests, atomic.LoadInt32(&requests), "Only reads are retried when RetryReadsOnly is set")
+		})
+	}
+}
+
+func TestIsReadRequest(t *testing.T) {
+	// given
+	get, _ := http.NewRequest(http.MethodGet, "https://api.equinix.com", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://api.equinix.com", nil)
+	// then
+	assert.True(t, isReadRequest(&http.Response{Request: get}, nil), "Responses to GET requests are reads")
+	assert.False(t, isReadRequest(&http.Response{Request: post}, nil), "Responses to POST requests are not reads")
+	assert.True(t, isReadRequest(nil, &url.Error{Op: "Get", URL: "https://api.equinix.com", Err: io.EOF}), "Failed GET requests are reads")
+	assert.False(t, isReadRequest(nil, &url.Error{Op: "Put", URL: "https://api.equinix.com", Err: io.EOF}), "Failed PUT requests are not reads")
+	assert.False(t, isReadRequest(nil, io.EOF), "Requests of unknown method are not reads")
+}
+
+func TestConfig_metalRetryPolicy_errors(t *testing.T) {
+	// given
+	config := Config{RetryableErrorSubstrings: []string{"connection reset"}}
+	// when
+	retryMatching, errMatching := config.metalRetryPolicy(context.Background(), nil, &url.Error{Op: "Get", URL: "https://api.equinix.com", Err: errors.New("stopped after 10 redirects: connection reset")})
+	retryOther, errOther := config.metalRetryPolicy(context.Background(), nil, &url.Error{Op: "Get", URL: "https://api.equinix.com", Err: errors.New("stopped after 10 redirects")})
+	// then
+	assert.NoError(t, errMatching)
+	assert.True(t, retryMatching, "Errors containing a substring are retried")
+	assert.NoError(t, errOther)
+	assert.False(t, retryOther, "Other errors follow the retry policy")
+}
+
+func TestConfig_Load_pageSize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pageSize int
+		expected int
+	}{
+		{"Default", 0, DefaultPageSize},
+		{"Custom", 500, 500},
+		{"Max", MaxPageSize, MaxPageSize},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			config := Config{BaseURL: DefaultBaseURL, Token: "token", PageSize: testCase.pageSize}
+			// when
+			err := config.Load(context.Background())
+			// then
+			assert.NoError(t, err, "Load does not return an error")
+			assert.Equal(t, testCase.expected, config.ne.(*ne.RestClient).PageSize, "NE client page size is set")
+		})
+	}
+}
+
+func TestConfig_Load_invalidPageSize(t *testing.T) {
+	for _, pageSize := range []int{-1, MaxPageSize + 1} {
+		// given
+		config := Config{BaseURL: DefaultBaseURL, Token: "token", PageSize: pageSize}
+		// when
+		err := config.Load(context.Background())
+		// then
+		assert.EqualError(t, err, fmt.Sprintf("'pageSize' must be between 0 (default %d) and %d, got: %d", DefaultPageSize, MaxPageSize, pageSize),
+			"Load returns an error for page size %d", pageSize)
+	}
+}
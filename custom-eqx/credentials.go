@@ -0,0 +1,114 @@
+package equinix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+	xoauth2 "golang.org/x/oauth2"
+)
+
+const (
+	credentialSourceKeyring = "keyring"
+	defaultKeyringService   = "terraform-provider-equinix"
+	defaultKeyringAccount   = "default"
+)
+
+// keyringGet reads a secret from the OS keyring
+var keyringGet = keyring.Get
+
+// loadKeyringCredentials reads the secret stored in the OS keyring under the
+// configured service and account names. The secret is used as the client secret
+// when a client ID is configured, and as the API token otherwise. Credentials
+// set explicitly take precedence over the keyring.
+func (c *Config) loadKeyringCredentials() error {
+	if c.Token != "" || c.ClientSecret != "" {
+		return nil
+	}
+	service := c.KeyringService
+	if service == "" {
+		service = defaultKeyringService
+	}
+	account := c.KeyringAccount
+	if account == "" {
+		account = defaultKeyringAccount
+	}
+
+	secret, err := keyringGet(service, account)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return fmt.Errorf("no credentials found in the OS keyring for service %q and account %q", service, account)
+		}
+		return fmt.Errorf("unable to read credentials from the OS keyring, it may be unavailable on this system: %s", err)
+	}
+
+	if c.ClientID != "" {
+		c.ClientSecret = secret
+	} else {
+		c.Token = secret
+	}
+	return nil
+}
+
+// rotatingTokenSource is the token source shared by the API clients. The
+// underlying source is replaced when the credentials are rotated.
+type rotatingTokenSource struct {
+	ctx    context.Context
+	client *http.Client
+
+	mu     sync.RWMutex
+	source xoauth2.TokenSource
+}
+
+func (s *rotatingTokenSource) Token() (*xoauth2.Token, error) {
+	s.mu.RLock()
+	source := s.source
+	s.mu.RUnlock()
+	return source.Token()
+}
+
+func (s *rotatingTokenSource) set(source xoauth2.TokenSource) {
+	s.mu.Lock()
+	s.source = source
+	s.mu.Unlock()
+}
+
+// UpdateCredentials replaces the API token used by the clients created in Load.
+// Subsequent requests are authorized with the new token, which FabricToken returns.
+func (c *Config) UpdateCredentials(token string) error {
+	if c.tokenSource == nil {
+		return fmt.Errorf("credentials cannot be updated before the configuration is loaded")
+	}
+	if token == "" {
+		return fmt.Errorf("'token' cannot be empty")
+	}
+	c.fabricTokenMu.Lock()
+	defer c.fabricTokenMu.Unlock()
+	c.tokenSource.set(xoauth2.StaticTokenSource(&xoauth2.Token{AccessToken: token}))
+	c.FabricAuthToken = token
+	return nil
+}
+
+// UpdateClientCredentials replaces the client credentials used by the clients
+// created in Load. A token is fetched with the new credentials, which becomes the
+// Fabric token, and the previous credentials are kept when that fails.
+func (c *Config) UpdateClientCredentials(clientID, clientSecret string) error {
+	if c.tokenSource == nil {
+		return fmt.Errorf("credentials cannot be updated before the configuration is loaded")
+	}
+	if clientID == "" || clientSecret == "" {
+		return fmt.Errorf("'clientID' and 'clientSecret' cannot be empty")
+	}
+	tke, err := c.fetchClientCredentialsToken(c.tokenSource.ctx, c.tokenSource.client, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+	c.fabricTokenMu.Lock()
+	defer c.fabricTokenMu.Unlock()
+	c.tokenSource.set(c.clientCredentialsTokenSource(c.tokenSource.ctx, c.tokenSource.client, clientID, clientSecret, tke))
+	c.FabricAuthToken = tke.AccessToken
+	return nil
+}
@@ -0,0 +1,156 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+func TestConfig_loadKeyringCredentials_clientSecret(t *testing.T) {
+	// given
+	keyring.MockInit()
+	assert.NoError(t, keyring.Set("equinix-test", "ci", "secret-from-keyring"))
+	config := Config{
+		ClientID:       "id",
+		KeyringService: "equinix-test",
+		KeyringAccount: "ci",
+	}
+	// when
+	err := config.loadKeyringCredentials()
+	// then
+	assert.NoError(t, err, "loadKeyringCredentials does not return an error")
+	assert.Equal(t, "secret-from-keyring", config.ClientSecret, "Client secret is read from keyring")
+	assert.Empty(t, config.Token, "Token is not set")
+}
+
+func TestConfig_loadKeyringCredentials_token(t *testing.T) {
+	// given
+	keyring.MockInit()
+	assert.NoError(t, keyring.Set(defaultKeyringService, defaultKeyringAccount, "token-from-keyring"))
+	config := Config{}
+	// when
+	err := config.loadKeyringCredentials()
+	// then
+	assert.NoError(t, err, "loadKeyringCredentials does not return an error")
+	assert.Equal(t, "token-from-keyring", config.Token, "Token is read from keyring using default names")
+}
+
+func TestConfig_loadKeyringCredentials_explicitCredentials(t *testing.T) {
+	// given
+	keyring.MockInit()
+	assert.NoError(t, keyring.Set(defaultKeyringService, defaultKeyringAccount, "token-from-keyring"))
+	config := Config{Token: "token"}
+	// when
+	err := config.loadKeyringCredentials()
+	// then
+	assert.NoError(t, err, "loadKeyringCredentials does not return an error")
+	assert.Equal(t, "token", config.Token, "Explicit token takes precedence")
+}
+
+func TestConfig_loadKeyringCredentials_notFound(t *testing.T) {
+	// given
+	keyring.MockInit()
+	config := Config{KeyringService: "equinix-test", KeyringAccount: "missing"}
+	// when
+	err := config.loadKeyringCredentials()
+	// then
+	assert.Error(t, err, "loadKeyringCredentials returns an error")
+	assert.Contains(t, err.Error(), "no credentials found", "Error describes missing credentials")
+	assert.Contains(t, err.Error(), "missing", "Error contains account name")
+}
+
+func TestConfig_loadKeyringCredentials_unavailable(t *testing.T) {
+	// given
+	get := keyringGet
+	defer func() { keyringGet = get }()
+	keyringGet = func(service, user string) (string, error) {
+		return "", fmt.Errorf("dbus: session bus is not available")
+	}
+	config := Config{}
+	// when
+	err := config.loadKeyringCredentials()
+	// then
+	assert.Error(t, err, "loadKeyringCredentials returns an error")
+	assert.Contains(t, err.Error(), "may be unavailable", "Error describes unavailable keyring")
+}
+
+func TestConfig_Load_invalidCredentialSource(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:          DefaultBaseURL,
+		Token:            "token",
+		CredentialSource: "vault",
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Error(t, err, "Load returns an error for unsupported credential source")
+}
+
+func TestConfig_UpdateCredentials(t *testing.T) {
+	// given
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case oauthTokenPath:
+			req := clientCredentialsTokenRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.ClientSecret != "rotated-secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(clientCredentialsTokenResponse{AccessToken: "client-token"})
+		default:
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+	config := Config{BaseURL: server.URL, Token: "initial-token"}
+	assert.NoError(t, config.Load(context.Background()))
+	// when
+	var fabricTokens []string
+	fabricToken := func() {
+		token, _ := config.FabricToken()
+		fabricTokens = append(fabricTokens, token)
+	}
+	_, _ = config.ne.GetSSHPublicKeys()
+	fabricToken()
+	errToken := config.UpdateCredentials("rotated-token")
+	_, _ = config.ne.GetSSHPublicKeys()
+	fabricToken()
+	errInvalid := config.UpdateClientCredentials("id", "wrong-secret")
+	_, _ = config.ne.GetSSHPublicKeys()
+	fabricToken()
+	errClient := config.UpdateClientCredentials("id", "rotated-secret")
+	_, _ = config.ne.GetSSHPublicKeys()
+	fabricToken()
+	// then
+	assert.NoError(t, errToken)
+	assert.Error(t, errInvalid, "Invalid client credentials are rejected")
+	assert.NoError(t, errClient)
+	assert.Equal(t, []string{"initial-token", "rotated-token", "rotated-token", "client-token"}, fabricTokens, "Fabric token follows the rotation")
+	assert.Equal(t, []string{
+		"Bearer initial-token",
+		"Bearer rotated-token",
+		"Bearer rotated-token",
+		"Bearer client-token",
+	}, authorizations, "Requests after rotation carry new credentials")
+}
+
+func TestConfig_UpdateCredentials_notLoaded(t *testing.T) {
+	// given
+	config := Config{}
+	// when
+	err := config.UpdateCredentials("token")
+	// then
+	assert.Error(t, err, "Credentials cannot be updated before Load")
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/artraf/custom-ne-go"
+	"github.com/artraf/equinix-custom-ne/custom-eqx/internal/datalist"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -26,7 +27,7 @@ var networkDeviceTypeDescriptions = map[string]string{
 	"Description": "Device type textual description",
 	"Vendor":      "Device type vendor i.e. Cisco, Juniper Networks, VERSA Networks",
 	"Category":    "Device type category, one of: Router, Firewall, SDWAN",
-	"MetroCodes":  "List of metro codes where device type has to be available",
+	"MetroCodes":  "List of metro codes where device type has to be available. Metro names, e.g. Ashburn, and lower case codes are accepted as well",
 }
 
 func dataSourceNetworkDeviceType() *schema.Resource {
@@ -72,7 +73,7 @@ func dataSourceNetworkDeviceType() *schema.Resource {
 				MinItems: 1,
 				Elem: &schema.Schema{
 					Type:         schema.TypeString,
-					ValidateFunc: stringIsMetroCode(),
+					ValidateFunc: stringIsMetroCodeOrName(),
 				},
 				Description: networkDeviceTypeDescriptions["MetroCodes"],
 			},
@@ -102,7 +103,7 @@ func dataSourceNetworkDeviceTypeRead(ctx context.Context, d *schema.ResourceData
 		if category != "" && !strings.EqualFold(ne.StringValue(deviceType.Category), category) {
 			continue
 		}
-		if !stringsFound(metroCodes, deviceType.MetroCodes) {
+		if !metroCodesFound(metroCodes, deviceType.MetroCodes) {
 			continue
 		}
 		filtered = append(filtered, deviceType)
@@ -119,6 +120,17 @@ func dataSourceNetworkDeviceTypeRead(ctx context.Context, d *schema.ResourceData
 	return diags
 }
 
+// metroCodesFound reports whether all of the metros are in the target metros,
+// comparing them by normalized metro code
+func metroCodesFound(metros []string, target []string) bool {
+	for i := range metros {
+		if !datalist.MetroIn(metros[i], target) {
+			return false
+		}
+	}
+	return true
+}
+
 func updateNetworkDeviceTypeResource(deviceType ne.DeviceType, d *schema.ResourceData) error {
 	d.SetId(ne.StringValue(deviceType.Code))
 	if err := d.Set(networkDeviceTypeSchemaNames["Name"], deviceType.Name); err != nil {
@@ -0,0 +1,44 @@
+package equinix
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkDeviceType_metroCodesFound(t *testing.T) {
+	// given
+	available := []string{"SV", "DC", "LD"}
+	// when then
+	for _, metro := range []string{"Ashburn", "DC", "dc"} {
+		assert.True(t, metroCodesFound([]string{metro}, available), "Metro %q is found", metro)
+	}
+	assert.True(t, metroCodesFound([]string{"ashburn", "Silicon Valley"}, available), "All metros are found")
+	assert.False(t, metroCodesFound([]string{"DC", "AM"}, available), "Missing metro is not found")
+	assert.True(t, metroCodesFound(nil, available), "Empty metro list is found")
+}
+
+func TestNetworkDeviceType_metroCodesSchema(t *testing.T) {
+	testCases := []struct {
+		metro         string
+		expectedValid bool
+	}{
+		{"DC", true},
+		{"dc", true},
+		{"Ashburn", true},
+		{"Atlantis", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.metro, func(t *testing.T) {
+			// given
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				networkDeviceTypeSchemaNames["MetroCodes"]: []interface{}{testCase.metro},
+			})
+			// when
+			diags := dataSourceNetworkDeviceType().Validate(config)
+			// then
+			assert.Equal(t, !testCase.expectedValid, diags.HasError(), "Metro %q validation: %v", testCase.metro, diags)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/artraf/equinix-custom-ne/custom-eqx/internal/datalist"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const networkDevicesPath = "/ne/v1/devices"
+
+var networkDevicesSchemaNames = map[string]string{
+	"UUID":           "uuid",
+	"Name":           "name",
+	"TypeCode":       "type_code",
+	"Status":         "status",
+	"LicenseStatus":  "license_status",
+	"MetroCode":      "metro_code",
+	"Hostname":       "hostname",
+	"AccountNumber":  "account_number",
+	"ProjectID":      "project_id",
+	"RedundancyType": "redundancy_type",
+	"RedundantUUID":  "redundant_id",
+}
+
+var networkDevicesDescriptions = map[string]string{
+	"UUID":           "Device unique identifier",
+	"Name":           "Device name",
+	"TypeCode":       "Device type code",
+	"Status":         "Device provisioning status",
+	"LicenseStatus":  "Device license registration status",
+	"MetroCode":      "Device location metro code",
+	"Hostname":       "Device hostname",
+	"AccountNumber":  "Device billing account number",
+	"ProjectID":      "Device project identifier",
+	"RedundancyType": "Device redundancy type, PRIMARY or SECONDARY for redundant devices",
+	"RedundantUUID":  "Unique identifier of the redundant device, if any",
+}
+
+// networkDevicesPage is the part of a page of the Network Edge device list used by
+// the network devices data source.
+type networkDevicesPage struct {
+	Pagination struct {
+		Total int `json:"total"`
+	} `json:"pagination"`
+	Data []networkDevicesRecord `json:"data"`
+}
+
+type networkDevicesRecord struct {
+	UUID           string `json:"uuid"`
+	Name           string `json:"name"`
+	DeviceTypeCode string `json:"deviceTypeCode"`
+	Status         string `json:"status"`
+	LicenseStatus  string `json:"licenseStatus"`
+	MetroCode      string `json:"metroCode"`
+	HostName       string `json:"hostName"`
+	AccountNumber  string `json:"accountNumber"`
+	ProjectID      string `json:"projectId"`
+	RedundancyType string `json:"redundancyType"`
+	RedundantUUID  string `json:"redundantUuid"`
+}
+
+func dataSourceNetworkDevices() *schema.Resource {
+	recordSchema := map[string]*schema.Schema{}
+	for key, name := range networkDevicesSchemaNames {
+		recordSchema[name] = &schema.Schema{
+			Type:        schema.TypeString,
+			Description: networkDevicesDescriptions[key],
+		}
+	}
+	resource := datalist.NewResource(&datalist.ResourceConfig{
+		RecordSchema:               recordSchema,
+		ResultAttributeName:        "devices",
+		ResultAttributeDescription: "Network Edge devices matching the filters",
+		FlattenRecord:              flattenNetworkDevicesRecord,
+		GetRecordsPage:             getNetworkDevicesPage,
+		EnumAliases: map[string]map[string]string{
+			networkDevicesSchemaNames["Status"]: datalist.StatusAliases,
+		},
+		QueryParameters: map[string]string{
+			networkDevicesSchemaNames["Status"]: "status",
+		},
+		ProgressFunc: dataListProgress,
+	})
+	resource.Description = "Use this data source to list Equinix Network Edge devices, selected with filters"
+	return resource
+}
+
+func flattenNetworkDevicesRecord(record, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+	device := record.(networkDevicesRecord)
+	return map[string]interface{}{
+		networkDevicesSchemaNames["UUID"]:           device.UUID,
+		networkDevicesSchemaNames["Name"]:           device.Name,
+		networkDevicesSchemaNames["TypeCode"]:       device.DeviceTypeCode,
+		networkDevicesSchemaNames["Status"]:         device.Status,
+		networkDevicesSchemaNames["LicenseStatus"]:  device.LicenseStatus,
+		networkDevicesSchemaNames["MetroCode"]:      device.MetroCode,
+		networkDevicesSchemaNames["Hostname"]:       device.HostName,
+		networkDevicesSchemaNames["AccountNumber"]:  device.AccountNumber,
+		networkDevicesSchemaNames["ProjectID"]:      device.ProjectID,
+		networkDevicesSchemaNames["RedundancyType"]: device.RedundancyType,
+		networkDevicesSchemaNames["RedundantUUID"]:  device.RedundantUUID,
+	}, nil
+}
+
+// getNetworkDevicesPage loads a page of the Network Edge device list. The API client
+// loads all of the pages at once, so the list is requested directly, which lets the
+// data source stop at its limit and report its progress.
+func getNetworkDevicesPage(ctx context.Context, meta interface{}, extra map[string]interface{}, offset, limit int) ([]interface{}, int, error) {
+	conf := meta.(*Config)
+	client, err := conf.ServiceHTTPClient("ne")
+	if err != nil {
+		return nil, 0, err
+	}
+	query := url.Values{}
+	if pushed, ok := extra[datalist.PushdownQueryKey].(url.Values); ok {
+		for name, values := range pushed {
+			query[name] = values
+		}
+	}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, conf.BaseURL+networkDevicesPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, 0, fmt.Errorf("listing network devices failed with status %d", resp.StatusCode)
+	}
+	page := networkDevicesPage{}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode the network devices: %s", err)
+	}
+	records := make([]interface{}, len(page.Data))
+	for i := range page.Data {
+		records[i] = page.Data[i]
+	}
+	return records, page.Pagination.Total, nil
+}
@@ -0,0 +1,79 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkDevices_read(t *testing.T) {
+	// given
+	const total = 150
+	var statuses []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, networkDevicesPath, r.URL.Path)
+		statuses = append(statuses, r.URL.Query().Get("status"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page := networkDevicesPage{}
+		page.Pagination.Total = total
+		for i := offset; i < offset+limit && i < total; i++ {
+			page.Data = append(page.Data, networkDevicesRecord{
+				UUID:   fmt.Sprintf("uuid-%03d", i),
+				Name:   fmt.Sprintf("device-%03d", i),
+				Status: "PROVISIONED",
+			})
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+	type progressReport struct{ fetched, total int }
+	var reports []progressReport
+	config := &Config{BaseURL: server.URL, Token: "token", ProgressFunc: func(fetched, total int) {
+		reports = append(reports, progressReport{fetched, total})
+	}}
+	assert.NoError(t, config.Load(context.Background()))
+	resource := dataSourceNetworkDevices()
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": networkDevicesSchemaNames["Status"], "values": []interface{}{"PROVISIONED"}},
+		},
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, config)
+	// then
+	assert.False(t, diags.HasError(), "Read does not return errors: %v", diags)
+	devices := d.Get("devices").([]interface{})
+	assert.Len(t, devices, total, "All of the pages are loaded")
+	assert.Equal(t, "device-149", devices[total-1].(map[string]interface{})[networkDevicesSchemaNames["Name"]])
+	assert.Equal(t, []string{"PROVISIONED", "PROVISIONED"}, statuses, "Status filter is pushed down to each page request")
+	assert.Equal(t, []progressReport{{100, total}, {total, total}}, reports, "Progress is reported after each page")
+}
+
+func TestNetworkDevices_readError(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	config := &Config{BaseURL: server.URL, Token: "token"}
+	assert.NoError(t, config.Load(context.Background()))
+	resource := dataSourceNetworkDevices()
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{})
+	// when
+	diags := resource.ReadContext(context.Background(), d, config)
+	// then
+	assert.True(t, diags.HasError(), "Read returns an error")
+}
+
+func TestDataListProgress(t *testing.T) {
+	assert.Nil(t, dataListProgress(&Config{}), "Progress is not reported without ProgressFunc")
+	assert.Nil(t, dataListProgress(nil), "Progress is not reported without configuration")
+}
@@ -0,0 +1,127 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// idSegmentRe matches the path segments identifying resources, i.e. UUIDs and numbers.
+var idSegmentRe = regexp.MustCompile(`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9]+)$`)
+
+// deprecationState holds the API endpoints reported as deprecated, each of which is
+// only warned about once.
+type deprecationState struct {
+	mu      sync.Mutex
+	warned  map[string]bool
+	pending diag.Diagnostics
+}
+
+// Records the deprecation of the endpoint of the request, unless it was already
+// recorded.
+func (s *deprecationState) record(service string, req *http.Request, header http.Header) {
+	endpoint := fmt.Sprintf("%s %s", req.Method, endpointPath(req.URL.Path))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.warned[endpoint] {
+		return
+	}
+	if s.warned == nil {
+		s.warned = map[string]bool{}
+	}
+	s.warned[endpoint] = true
+
+	detail := fmt.Sprintf("The Equinix %s API reports that %s is deprecated.", service, endpoint)
+	if deprecation := header.Get("Deprecation"); deprecation != "" {
+		detail += fmt.Sprintf(" Deprecation: %s.", deprecation)
+	}
+	if sunset := header.Get("Sunset"); sunset != "" {
+		detail += fmt.Sprintf(" It will stop working after %s.", sunset)
+	}
+	if links := header.Values("Link"); len(links) > 0 {
+		detail += fmt.Sprintf(" See: %s.", links[0])
+	}
+	log.Printf("[WARN] %s", detail)
+	s.pending = append(s.pending, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Deprecated Equinix API endpoint",
+		Detail:   detail + " Upgrade the provider, or report the issue if this version is the latest.",
+	})
+}
+
+// Returns the path with the segments identifying resources replaced by {id}, so that
+// the requests for different resources of an endpoint are warned about once.
+func endpointPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idSegmentRe.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Returns the warnings recorded since the last call.
+func (s *deprecationState) take() diag.Diagnostics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending
+	s.pending = nil
+	return pending
+}
+
+// deprecationTransport is a RoundTripper recording the endpoints whose responses
+// carry the Deprecation or Sunset headers.
+type deprecationTransport struct {
+	service string
+	state   *deprecationState
+	next    http.RoundTripper
+}
+
+func (t *deprecationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.Header.Get("Deprecation") != "" || resp.Header.Get("Sunset") != "" {
+		t.state.record(t.service, req, resp.Header)
+	}
+	return resp, nil
+}
+
+type operationFunc = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+// Appends the warnings about the deprecated API endpoints to the diagnostics of the
+// operations of the resource. Since the API clients do not tell which operation sends
+// a request, the warnings are attached to the first operation completing after the
+// requests to the endpoints.
+func withDeprecationWarnings(r *schema.Resource) {
+	wrap := func(f operationFunc) operationFunc {
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			diags := f(ctx, d, meta)
+			if c, ok := meta.(*Config); ok {
+				diags = append(diags, c.deprecations.take()...)
+			}
+			return diags
+		}
+	}
+	if r.CreateContext != nil {
+		r.CreateContext = wrap(r.CreateContext)
+	}
+	if r.ReadContext != nil {
+		r.ReadContext = wrap(r.ReadContext)
+	}
+	if r.UpdateContext != nil {
+		r.UpdateContext = wrap(r.UpdateContext)
+	}
+	if r.DeleteContext != nil {
+		r.DeleteContext = wrap(r.DeleteContext)
+	}
+}
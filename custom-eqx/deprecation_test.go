@@ -0,0 +1,79 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationTransport(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			w.Header().Set("Sunset", "Wed, 11 Nov 2026 23:59:59 GMT")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	config := &Config{BaseURL: DefaultBaseURL, Token: "token"}
+	assert.NoError(t, config.Load(context.Background()))
+	client := &http.Client{Transport: config.serviceTransport("ne", http.DefaultTransport)}
+	resource := &schema.Resource{
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			for _, path := range []string{"/old", "/old", "/current"} {
+				resp, err := client.Get(server.URL + path)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+				resp.Body.Close()
+			}
+			return nil
+		},
+	}
+	withDeprecationWarnings(resource)
+	// when
+	first := resource.ReadContext(context.Background(), nil, config)
+	second := resource.ReadContext(context.Background(), nil, config)
+	// then
+	assert.Len(t, first, 1, "A single warning is reported for the deprecated endpoint")
+	assert.Equal(t, diag.Warning, first[0].Severity)
+	assert.Contains(t, first[0].Detail, "GET /old")
+	assert.Contains(t, first[0].Detail, "Wed, 11 Nov 2026 23:59:59 GMT")
+	assert.Empty(t, second, "The endpoint is only warned about once")
+}
+
+func TestDeprecationTransport_resourceIDs(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	state := &deprecationState{}
+	client := &http.Client{Transport: &deprecationTransport{service: "ne", state: state, next: http.DefaultTransport}}
+	// when
+	for _, path := range []string{
+		"/ne/v1/devices/3f8e9b4c-0d5a-4c6e-9b1f-2a7d8e6c5b40/acl",
+		"/ne/v1/devices/a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d/acl",
+		"/ne/v1/devices/3f8e9b4c-0d5a-4c6e-9b1f-2a7d8e6c5b40",
+	} {
+		resp, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	warnings := state.take()
+	// then
+	assert.Len(t, warnings, 2, "Requests to the same endpoint for different devices are warned about once")
+	assert.Contains(t, warnings[0].Detail, "GET /ne/v1/devices/{id}/acl")
+	assert.Contains(t, warnings[1].Detail, "GET /ne/v1/devices/{id} ")
+}
+
+func TestEndpointPath(t *testing.T) {
+	assert.Equal(t, "/ne/v1/devices/{id}/interfaces/{id}", endpointPath("/ne/v1/devices/3F8E9B4C-0D5A-4C6E-9B1F-2A7D8E6C5B40/interfaces/12"))
+	assert.Equal(t, "/ne/v1/deviceTypes", endpointPath("/ne/v1/deviceTypes"), "Paths without identifiers are kept")
+}
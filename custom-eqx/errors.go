@@ -2,10 +2,12 @@ package equinix
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"sort"
 	"strings"
 
+	"github.com/equinix/rest-go"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -53,6 +55,12 @@ func isForbidden(err error) bool {
 }
 
 func isNotFound(err error) bool {
+	switch r := err.(type) {
+	case rest.Error:
+		return r.HTTPCode == http.StatusNotFound
+	case *rest.Error:
+		return r.HTTPCode == http.StatusNotFound
+	}
 	if r, ok := err.(*ErrorResponse); ok {
 		return r.StatusCode == http.StatusNotFound && r.IsAPIError
 	}
@@ -62,6 +70,18 @@ func isNotFound(err error) bool {
 	return false
 }
 
+// removeIfNotFound removes the resource from the state when the read error reports
+// that the resource does not exist. With StrictNotFound set, the resource is kept and
+// the error is expected to be returned.
+func (c *Config) removeIfNotFound(d *schema.ResourceData, err error) bool {
+	if c.StrictNotFound || !isNotFound(err) {
+		return false
+	}
+	log.Printf("[WARN] Resource %q not found, removing from state", d.Id())
+	d.SetId("")
+	return true
+}
+
 type Errors []string
 
 func (e Errors) Error() string {
@@ -0,0 +1,109 @@
+package equinix
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// failoverTransport is a RoundTripper resending the requests to the fallback base
+// URLs, in order, when the primary base URL cannot be connected to. Requests failing
+// once connected, e.g. with a timeout or a connection reset, are not resent as the
+// API may have processed them, and HTTP error responses are returned as they are. The
+// request headers, including authorization and user agent, are preserved.
+type failoverTransport struct {
+	primary   *url.URL
+	fallbacks []*url.URL
+	next      http.RoundTripper
+}
+
+func newFailoverTransport(primary string, fallbacks []string, next http.RoundTripper) (*failoverTransport, error) {
+	primaryURL, err := parseBaseURL(primary)
+	if err != nil {
+		return nil, fmt.Errorf("'baseURL' must be an absolute URL, got: %q", primary)
+	}
+	t := &failoverTransport{primary: primaryURL, next: next}
+	for _, fallback := range fallbacks {
+		fallbackURL, err := parseBaseURL(fallback)
+		if err != nil {
+			return nil, fmt.Errorf("'fallbackBaseURLs' must contain absolute URLs, got: %q", fallback)
+		}
+		t.fallbacks = append(t.fallbacks, fallbackURL)
+	}
+	return t, nil
+}
+
+func parseBaseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("not an absolute URL: %q", s)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err == nil || !isConnectError(err) || !t.isPrimary(req.URL) {
+		return resp, err
+	}
+	for _, fallback := range t.fallbacks {
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		fallbackReq, rerr := t.rebase(req, fallback)
+		if rerr != nil {
+			return nil, err
+		}
+		resp, err = t.next.RoundTrip(fallbackReq)
+		if err == nil || !isConnectError(err) {
+			return resp, err
+		}
+	}
+	return nil, err
+}
+
+// Reports whether the request failed before reaching the server, when resolving or
+// dialing its host, so that it can safely be sent elsewhere.
+func isConnectError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (t *failoverTransport) isPrimary(u *url.URL) bool {
+	if u.Scheme != t.primary.Scheme || u.Host != t.primary.Host {
+		return false
+	}
+	return t.primary.Path == "" || u.Path == t.primary.Path || strings.HasPrefix(u.Path, t.primary.Path+"/")
+}
+
+// rebase returns a copy of the request sent to the fallback base URL, with a fresh body.
+func (t *failoverTransport) rebase(req *http.Request, fallback *url.URL) (*http.Request, error) {
+	rebased := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("request body cannot be replayed")
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		rebased.Body = body
+	}
+	rebased.URL.Scheme = fallback.Scheme
+	rebased.URL.Host = fallback.Host
+	rebased.URL.Path = fallback.Path + strings.TrimPrefix(req.URL.Path, t.primary.Path)
+	rebased.URL.RawPath = ""
+	rebased.Host = ""
+	return rebased, nil
+}
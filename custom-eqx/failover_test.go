@@ -0,0 +1,137 @@
+package equinix
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailoverTransport(t *testing.T) {
+	// given
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	var received []string
+	var headers []http.Header
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.Method+" "+r.URL.Path+" "+string(body))
+		headers = append(headers, r.Header)
+		if r.URL.Path == "/secondary/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer live.Close()
+	transport, err := newFailoverTransport(dead.URL+"/primary", []string{dead.URL, live.URL + "/secondary/"}, http.DefaultTransport)
+	assert.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	// when
+	req, _ := http.NewRequest(http.MethodPost, dead.URL+"/primary/ne/v1/devices", bytes.NewBufferString(`{"name":"router"}`))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("User-agent", "equinix/ne-go")
+	resp, err := client.Do(req)
+	// then
+	assert.NoError(t, err, "Request fails over to live secondary")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{`POST /secondary/ne/v1/devices {"name":"router"}`}, received, "Path and body are preserved")
+	assert.Equal(t, "Bearer token", headers[0].Get("Authorization"), "Authorization is preserved")
+	assert.Equal(t, "equinix/ne-go", headers[0].Get("User-agent"), "User agent is preserved")
+
+	// when
+	resp, err = client.Get(live.URL + "/secondary/missing")
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "HTTP errors are not failed over")
+	assert.Len(t, received, 2)
+}
+
+func TestFailoverTransport_allDead(t *testing.T) {
+	// given
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	transport, err := newFailoverTransport(dead.URL, []string{dead.URL + "/other"}, http.DefaultTransport)
+	assert.NoError(t, err)
+	// when
+	_, err = (&http.Client{Transport: transport}).Get(dead.URL + "/ne/v1/devices")
+	// then
+	assert.Error(t, err, "Error is returned when all base URLs fail")
+}
+
+func TestConfig_Load_invalidFallbackBaseURLs(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:          DefaultBaseURL,
+		Token:            "token",
+		FallbackBaseURLs: []string{"api.equinix.com"},
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "'fallbackBaseURLs'")
+}
+
+func TestFailoverTransport_notFailedOver(t *testing.T) {
+	// given
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	reset := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The request reached the server, which may have processed it
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer reset.Close()
+	received := 0
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer live.Close()
+	testCases := []struct {
+		name    string
+		primary string
+		url     string
+	}{
+		{"ConnectionReset", reset.URL, reset.URL + "/ne/v1/devices"},
+		{"SiblingPath", dead.URL + "/api", dead.URL + "/apiv2/ne/v1/devices"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			transport, err := newFailoverTransport(testCase.primary, []string{live.URL}, http.DefaultTransport)
+			assert.NoError(t, err)
+			// when
+			_, err = (&http.Client{Transport: transport}).Post(testCase.url, "application/json", bytes.NewBufferString(`{"name":"router"}`))
+			// then
+			assert.Error(t, err, "Error of the primary is returned")
+			assert.Equal(t, 0, received, "Request is not resent to the fallback")
+		})
+	}
+}
+
+func TestConfig_Load_failoverClientCredentials(t *testing.T) {
+	// given
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	var requested []string
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "token", "token_timeout": "3600"}`))
+	}))
+	defer live.Close()
+	config := Config{
+		BaseURL:          dead.URL,
+		FallbackBaseURLs: []string{live.URL},
+		ClientID:         "id",
+		ClientSecret:     "secret",
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.NoError(t, err, "Token is requested from the fallback")
+	assert.Equal(t, "token", config.FabricAuthToken)
+	assert.Equal(t, []string{oauthTokenPath}, requested)
+}
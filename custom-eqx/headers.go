@@ -0,0 +1,46 @@
+package equinix
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// reservedHeaders are set by the API clients and cannot be overridden by the
+// extra headers.
+var reservedHeaders = []string{"Authorization", "User-Agent", "X-Auth-Token", "X-Consumer-Token"}
+
+func isReservedHeader(name string) bool {
+	canonical := http.CanonicalHeaderKey(name)
+	for _, reserved := range reservedHeaders {
+		if canonical == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+func validateExtraHeaders(headers map[string]string) error {
+	for name := range headers {
+		if isReservedHeader(name) {
+			return fmt.Errorf("'extraHeaders' cannot override the %q header", name)
+		}
+	}
+	return nil
+}
+
+// extraHeadersTransport is a RoundTripper adding static headers to every request.
+// Reserved headers are never overwritten.
+type extraHeadersTransport struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (t *extraHeadersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		if !isReservedHeader(name) {
+			req.Header.Set(name, value)
+		}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,73 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Load_extraHeaders(t *testing.T) {
+	// given
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	config := Config{
+		BaseURL:      server.URL,
+		Token:        "token",
+		ExtraHeaders: map[string]string{"X-Org-Id": "org-1"},
+	}
+	assert.NoError(t, config.Load(context.Background()))
+	// when
+	_, err := config.ne.GetSSHPublicKeys()
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "org-1", received.Get("X-Org-Id"), "Extra header is sent")
+	assert.Equal(t, "Bearer token", received.Get("Authorization"), "Authorization is preserved")
+	assert.True(t, strings.HasSuffix(received.Get("User-Agent"), "equinix/ecx-go"), "User-Agent is preserved")
+}
+
+func TestExtraHeadersTransport_reservedHeaders(t *testing.T) {
+	// given
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+	transport := &extraHeadersTransport{
+		headers: map[string]string{"authorization": "Bearer other", "user-agent": "other", "X-Org-Id": "org-1"},
+		next:    http.DefaultTransport,
+	}
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("User-Agent", "equinix/ne-go")
+	// when
+	resp, err := transport.RoundTrip(req)
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "org-1", received.Get("X-Org-Id"))
+	assert.Equal(t, "Bearer token", received.Get("Authorization"), "Authorization is not clobbered")
+	assert.Equal(t, "equinix/ne-go", received.Get("User-Agent"), "User-Agent is not clobbered")
+	assert.Empty(t, req.Header.Get("X-Org-Id"), "Original request is not modified")
+}
+
+func TestConfig_Load_reservedExtraHeaders(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:      DefaultBaseURL,
+		Token:        "token",
+		ExtraHeaders: map[string]string{"authorization": "Bearer other"},
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Error(t, err, "Load rejects reserved extra headers")
+}
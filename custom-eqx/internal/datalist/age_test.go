@@ -0,0 +1,101 @@
+package datalist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func ageTestRecords(now time.Time) []map[string]interface{} {
+	return []map[string]interface{}{
+		{"name": "dev-1", "created_date": now.Add(-91 * 24 * time.Hour).Format(time.RFC3339)},
+		{"name": "dev-2", "created_date": now.Add(-2 * time.Hour).Format(time.RFC3339)},
+		{"name": "dev-3", "created_date": ""},
+		{"name": "dev-4", "created_date": now.Add(-30 * 24 * time.Hour).Format(time.RFC3339)},
+	}
+}
+
+func TestApplyFilters_age(t *testing.T) {
+	now := time.Date(2022, 12, 2, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	recordSchema := map[string]*schema.Schema{
+		"name":         {Type: schema.TypeString},
+		"created_date": {Type: schema.TypeString},
+	}
+	testCases := []struct {
+		name         string
+		matchBy      string
+		value        string
+		expectations []string
+	}{
+		{"OlderThan90Days", "age_gt", "2160h", []string{"dev-1"}},
+		{"OlderThanAnHour", "age_gt", "1h", []string{"dev-1", "dev-2", "dev-4"}},
+		{"NewerThan31Days", "age_lt", "744h", []string{"dev-2", "dev-4"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{
+					"attribute": "created_date",
+					"values":    []interface{}{testCase.value},
+					"match_by":  testCase.matchBy,
+				},
+			})
+			assert.NoError(t, err)
+			var names []string
+			for _, record := range applyFilters(recordSchema, ageTestRecords(now), filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectations, names)
+		})
+	}
+
+	_, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "created_date",
+			"values":    []interface{}{"90 days"},
+			"match_by":  "age_gt",
+		},
+	})
+	assert.Error(t, err, "Invalid duration is rejected")
+}
+
+func TestApplySorts_byAge(t *testing.T) {
+	now := time.Date(2022, 12, 2, 12, 0, 0, 0, time.UTC)
+	recordSchema := map[string]*schema.Schema{
+		"name":         {Type: schema.TypeString},
+		"created_date": {Type: schema.TypeString},
+		"cores":        {Type: schema.TypeInt},
+	}
+	testCases := []struct {
+		direction    string
+		expectations []string
+	}{
+		{"asc", []string{"dev-2", "dev-4", "dev-1", "dev-3"}},
+		{"desc", []string{"dev-3", "dev-1", "dev-4", "dev-2"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.direction, func(t *testing.T) {
+			sorts, err := expandSorts(recordSchema, []interface{}{
+				map[string]interface{}{"attribute": "created_date", "direction": testCase.direction, "by": "age"},
+			})
+			assert.NoError(t, err)
+			var names []string
+			for _, record := range applySorts(recordSchema, ageTestRecords(now), sorts) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectations, names)
+		})
+	}
+
+	_, err := expandSorts(recordSchema, []interface{}{
+		map[string]interface{}{"attribute": "cores", "direction": "asc", "by": "age"},
+	})
+	assert.EqualError(t, err, "sort by age is not supported by field 'cores', which is not a string")
+}
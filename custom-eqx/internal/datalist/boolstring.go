@@ -0,0 +1,38 @@
+package datalist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The tokens accepted as booleans by the bool mode of string attributes, which are
+// compared case-insensitively.
+var boolTokens = map[string]bool{
+	"true":     true,
+	"false":    false,
+	"yes":      true,
+	"no":       false,
+	"y":        true,
+	"n":        false,
+	"on":       true,
+	"off":      false,
+	"1":        true,
+	"0":        false,
+	"enabled":  true,
+	"disabled": false,
+}
+
+// Parses a boolean expressed as a string, reporting false when it is not one of
+// the known tokens.
+func parseBoolToken(value string) (bool, bool) {
+	b, ok := boolTokens[strings.ToLower(strings.TrimSpace(value))]
+	return b, ok
+}
+
+func expandBoolTokenFilterValue(value string) (bool, error) {
+	b, ok := parseBoolToken(value)
+	if !ok {
+		return false, fmt.Errorf("unable to parse value as boolean: %s, expected one of: true, false, yes, no, y, n, on, off, 1, 0, enabled, disabled", value)
+	}
+	return b, nil
+}
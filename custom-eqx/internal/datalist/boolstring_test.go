@@ -0,0 +1,93 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBoolToken(t *testing.T) {
+	testCases := map[string]struct {
+		expected bool
+		ok       bool
+	}{
+		"true":       {true, true},
+		"TRUE":       {true, true},
+		" Yes ":      {true, true},
+		"y":          {true, true},
+		"on":         {true, true},
+		"1":          {true, true},
+		"Enabled":    {true, true},
+		"false":      {false, true},
+		"No":         {false, true},
+		"N":          {false, true},
+		"off":        {false, true},
+		"0":          {false, true},
+		"disabled":   {false, true},
+		"":           {false, false},
+		"maybe":      {false, false},
+		"truthy":     {false, false},
+		"2":          {false, false},
+		"t r u e":    {false, false},
+		"yes please": {false, false},
+	}
+
+	for token, testCase := range testCases {
+		t.Run(token, func(t *testing.T) {
+			b, ok := parseBoolToken(token)
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.expected, b)
+		})
+	}
+}
+
+func TestApplyFilters_bool(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name":    {Type: schema.TypeString},
+		"managed": {Type: schema.TypeString},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "managed": "true"},
+		{"name": "dev-2", "managed": "No"},
+		{"name": "dev-3", "managed": "YES"},
+		{"name": "dev-4", "managed": "unknown"},
+		{"name": "dev-5", "managed": "0"},
+	}
+	testCases := []struct {
+		value        string
+		expectations []string
+	}{
+		{"true", []string{"dev-1", "dev-3"}},
+		{"yes", []string{"dev-1", "dev-3"}},
+		{"false", []string{"dev-2", "dev-5"}},
+		{"Off", []string{"dev-2", "dev-5"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{
+					"attribute": "managed",
+					"values":    []interface{}{testCase.value},
+					"match_by":  "bool",
+				},
+			})
+			assert.NoError(t, err)
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectations, names)
+		})
+	}
+
+	_, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "managed",
+			"values":    []interface{}{"maybe"},
+			"match_by":  "bool",
+		},
+	})
+	assert.Error(t, err, "Unparseable filter value is rejected")
+}
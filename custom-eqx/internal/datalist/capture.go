@@ -0,0 +1,134 @@
+package datalist
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const expressionCapture = "capture"
+
+// captureFilter compares a group captured by a regular expression from a string
+// attribute with a literal value or with the value of another attribute.
+type captureFilter struct {
+	attribute       string
+	re              *regexp.Regexp
+	group           int
+	equals          string
+	equalsAttribute string
+}
+
+// Parses a `capture` filter expression node, e.g.
+// {"attribute": "port_name", "pattern": "^([A-Z]{2})\\d+-", "equals_attribute": "metro_code"}.
+// The captured group is 1 unless `group` gives another index or a group name, and it is
+// compared case-insensitively with either `equals` or `equals_attribute`.
+func expandCaptureFilter(recordSchema map[string]*schema.Schema, raw interface{}) (*captureFilter, error) {
+	node, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%q filter expression node must be an object", expressionCapture)
+	}
+	f := &captureFilter{group: 1}
+	var rawGroup interface{}
+	var hasEquals bool
+	for k, v := range node {
+		switch k {
+		case "attribute", "equals_attribute":
+			attr, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("capture filter key %q must be a string", k)
+			}
+			s, ok := recordSchema[attr]
+			if !ok {
+				return nil, fmt.Errorf("field '%s' does not exist in record schema", attr)
+			}
+			if k == "attribute" {
+				if s.Type != schema.TypeString {
+					return nil, fmt.Errorf("capture filter field '%s' must be a string, got: %s", attr, s.Type)
+				}
+				f.attribute = attr
+			} else {
+				if !isPrimitiveType(s.Type) {
+					return nil, fmt.Errorf("capture filter field '%s' must be primitive, got: %s", attr, s.Type)
+				}
+				f.equalsAttribute = attr
+			}
+		case "pattern":
+			pattern, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("capture filter key %q must be a string", k)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse value as regular expression: %s: %s", pattern, err)
+			}
+			f.re = re
+		case "group":
+			rawGroup = v
+		case "equals":
+			equals, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("capture filter key %q must be a string", k)
+			}
+			f.equals = equals
+			hasEquals = true
+		default:
+			return nil, fmt.Errorf("unsupported capture filter key: %q", k)
+		}
+	}
+	if f.attribute == "" || f.re == nil {
+		return nil, fmt.Errorf("capture filter requires both attribute and pattern")
+	}
+	if hasEquals == (f.equalsAttribute != "") {
+		return nil, fmt.Errorf("capture filter requires exactly one of equals or equals_attribute")
+	}
+	if rawGroup != nil {
+		group, err := captureGroupIndex(f.re, rawGroup)
+		if err != nil {
+			return nil, err
+		}
+		f.group = group
+	}
+	if f.group < 0 || f.group > f.re.NumSubexp() {
+		return nil, fmt.Errorf("capture filter pattern %q has no group %d", f.re, f.group)
+	}
+	return f, nil
+}
+
+// Returns the index of the capture group given by its index or name.
+func captureGroupIndex(re *regexp.Regexp, group interface{}) (int, error) {
+	switch g := group.(type) {
+	case float64:
+		if g != float64(int(g)) {
+			return 0, fmt.Errorf("capture filter group must be an integer, got: %v", g)
+		}
+		return int(g), nil
+	case string:
+		if index := re.SubexpIndex(g); index >= 0 {
+			return index, nil
+		}
+		return 0, fmt.Errorf("capture filter pattern %q has no group named %q", re, g)
+	}
+	return 0, fmt.Errorf("capture filter key \"group\" must be a number or a string")
+}
+
+func (f *captureFilter) matches(record map[string]interface{}) bool {
+	value, ok := record[f.attribute].(string)
+	if !ok {
+		return false
+	}
+	submatches := f.re.FindStringSubmatch(value)
+	if submatches == nil {
+		return false
+	}
+	expected := f.equals
+	if f.equalsAttribute != "" {
+		other, ok := record[f.equalsAttribute]
+		if !ok || other == nil {
+			return false
+		}
+		expected = fmt.Sprint(other)
+	}
+	return strings.EqualFold(submatches[f.group], expected)
+}
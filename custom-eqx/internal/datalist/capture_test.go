@@ -0,0 +1,90 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func captureTestSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+		"slot":       {Type: schema.TypeInt},
+		"tags":       {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+}
+
+func captureTestData() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"name": "DC5-port-1", "metro_code": "DC", "slot": 1},
+		{"name": "SV1-port-2", "metro_code": "DC", "slot": 3},
+		{"name": "sv2-port-2", "metro_code": "SV", "slot": 2},
+		{"name": "port-3", "metro_code": "SV", "slot": 3},
+	}
+}
+
+func TestApplyFilterExpression_capture(t *testing.T) {
+	testCases := []struct {
+		name          string
+		expression    string
+		expectedNames []string
+	}{
+		{
+			"EqualsLiteral",
+			`{"capture": {"attribute": "name", "pattern": "^([A-Z]{2})\\d+-", "equals": "sv"}}`,
+			[]string{"SV1-port-2"},
+		},
+		{
+			"EqualsAttribute",
+			`{"capture": {"attribute": "name", "pattern": "^([a-zA-Z]{2})\\d+-", "equals_attribute": "metro_code"}}`,
+			[]string{"DC5-port-1", "sv2-port-2"},
+		},
+		{
+			"NamedGroupEqualsNumericAttribute",
+			`{"capture": {"attribute": "name", "pattern": "-port-(?P<slot>\\d+)$", "group": "slot", "equals_attribute": "slot"}}`,
+			[]string{"DC5-port-1", "sv2-port-2"},
+		},
+		{
+			"GroupIndex",
+			`{"capture": {"attribute": "name", "pattern": "^(\\w{2})(\\d)-", "group": 2, "equals": "2"}}`,
+			[]string{"sv2-port-2"},
+		},
+		{
+			"Negated",
+			`{"not": {"capture": {"attribute": "name", "pattern": "^([a-zA-Z]{2})\\d+-", "equals_attribute": "metro_code"}}}`,
+			[]string{"SV1-port-2", "port-3"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expression, err := expandFilterExpression(captureTestSchema(), testCase.expression)
+			if err != nil {
+				t.Fatalf("expandFilterExpression returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilterExpression(captureTestSchema(), captureTestData(), expression) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectedNames, names)
+		})
+	}
+}
+
+func TestExpandFilterExpression_invalidCapture(t *testing.T) {
+	for _, expression := range []string{
+		`{"capture": {"attribute": "slot", "pattern": "(\\d)", "equals": "1"}}`,
+		`{"capture": {"attribute": "name", "equals": "DC"}}`,
+		`{"capture": {"attribute": "name", "pattern": "([A-Z]"}}`,
+		`{"capture": {"attribute": "name", "pattern": "([A-Z]{2})"}}`,
+		`{"capture": {"attribute": "name", "pattern": "([A-Z]{2})", "equals": "DC", "equals_attribute": "metro_code"}}`,
+		`{"capture": {"attribute": "name", "pattern": "([A-Z]{2})", "equals_attribute": "tags"}}`,
+		`{"capture": {"attribute": "name", "pattern": "([A-Z]{2})", "group": 2, "equals": "DC"}}`,
+		`{"capture": {"attribute": "name", "pattern": "([A-Z]{2})", "group": "site", "equals": "DC"}}`,
+	} {
+		_, err := expandFilterExpression(captureTestSchema(), expression)
+		assert.Error(t, err, "expression %s", expression)
+	}
+}
@@ -0,0 +1,55 @@
+package datalist
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Converts a filter value, given as a string in the configuration, to the Go type of
+// the attribute's values, so it can be compared with them. Surrounding whitespace is
+// ignored, and integers can be written in the floating point notation, e.g. 1e3.
+func coerceFilterValue(value string, fieldType schema.ValueType) (interface{}, error) {
+	trimmed := strings.TrimSpace(value)
+	switch fieldType {
+	case schema.TypeString:
+		return value, nil
+
+	case schema.TypeBool:
+		boolValue, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert value %q to bool, expected true or false", value)
+		}
+		return boolValue, nil
+
+	case schema.TypeInt:
+		if intValue, err := strconv.Atoi(trimmed); err == nil {
+			return intValue, nil
+		}
+		floatValue, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil || floatValue != math.Trunc(floatValue) || math.Abs(floatValue) > math.MaxInt32 {
+			return nil, fmt.Errorf("unable to convert value %q to integer", value)
+		}
+		return int(floatValue), nil
+
+	case schema.TypeFloat:
+		floatValue, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil || math.IsNaN(floatValue) || math.IsInf(floatValue, 0) {
+			return nil, fmt.Errorf("unable to convert value %q to floating point", value)
+		}
+		return floatValue, nil
+	}
+	return nil, fmt.Errorf("unable to convert value %q to %s", value, fieldType)
+}
+
+// Formats a filter value given in a filter expression, where numbers and booleans
+// can be written as JSON literals, as in the configuration.
+func formatFilterValue(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,67 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoerceFilterValue(t *testing.T) {
+	testCases := []struct {
+		value     string
+		fieldType schema.ValueType
+		expected  interface{}
+	}{
+		{" name ", schema.TypeString, " name "},
+		{"42", schema.TypeInt, 42},
+		{" -7 ", schema.TypeInt, -7},
+		{"1e3", schema.TypeInt, 1000},
+		{"8.0", schema.TypeInt, 8},
+		{"2.5", schema.TypeFloat, 2.5},
+		{"10", schema.TypeFloat, 10.0},
+		{"true", schema.TypeBool, true},
+		{" FALSE", schema.TypeBool, false},
+	}
+	for _, testCase := range testCases {
+		coerced, err := coerceFilterValue(testCase.value, testCase.fieldType)
+		assert.NoError(t, err, "value %q", testCase.value)
+		assert.Equal(t, testCase.expected, coerced, "value %q", testCase.value)
+	}
+}
+
+func TestCoerceFilterValue_invalid(t *testing.T) {
+	testCases := []struct {
+		value     string
+		fieldType schema.ValueType
+		expected  string
+	}{
+		{"2.5", schema.TypeInt, `unable to convert value "2.5" to integer`},
+		{"1e20", schema.TypeInt, `unable to convert value "1e20" to integer`},
+		{"ten", schema.TypeInt, `unable to convert value "ten" to integer`},
+		{"NaN", schema.TypeFloat, `unable to convert value "NaN" to floating point`},
+		{"yes", schema.TypeBool, `unable to convert value "yes" to bool, expected true or false`},
+	}
+	for _, testCase := range testCases {
+		_, err := coerceFilterValue(testCase.value, testCase.fieldType)
+		assert.EqualError(t, err, testCase.expected)
+	}
+}
+
+func TestExpandFilters_uncoercibleValue(t *testing.T) {
+	_, err := expandFilters(sizesTestSchema(), []interface{}{
+		map[string]interface{}{
+			"attribute": "memory",
+			"values":    []interface{}{"8GB"},
+		},
+	})
+	assert.EqualError(t, err, `invalid filter value for field 'memory': unable to convert value "8GB" to integer`)
+}
+
+func TestExpandFilterExpression_coercesJSONNumbers(t *testing.T) {
+	expression, err := expandFilterExpression(sizesTestSchema(), `{"attribute": "memory", "values": [1e6, 8192]}`)
+	if err != nil {
+		t.Fatalf("expandFilterExpression returned error: %s", err)
+	}
+	assert.Equal(t, []interface{}{1000000, 8192}, expression.filter.values)
+}
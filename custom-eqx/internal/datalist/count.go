@@ -0,0 +1,72 @@
+package datalist
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// elementCount constrains the number of elements of a list or set matching a filter.
+// Negative bounds are not checked.
+type elementCount struct {
+	atLeast int
+	atMost  int
+}
+
+func (c elementCount) matches(count int) bool {
+	return (c.atLeast < 0 || count >= c.atLeast) && (c.atMost < 0 || count <= c.atMost)
+}
+
+// Expands the at_least and at_most bounds of a filter, which are only supported by
+// list and set attributes.
+func expandElementCount(attr string, s *schema.Schema, matchBy string, f map[string]interface{}) (*elementCount, error) {
+	count := elementCount{atLeast: -1, atMost: -1}
+	if v, ok := f["at_least"].(int); ok {
+		count.atLeast = v
+	}
+	if v, ok := f["at_most"].(int); ok {
+		count.atMost = v
+	}
+	if count.atLeast < 0 && count.atMost < 0 {
+		return nil, nil
+	}
+	if s.Type != schema.TypeList && s.Type != schema.TypeSet {
+		return nil, fmt.Errorf("at_least and at_most are not supported by field '%s' of type %s", attr, s.Type)
+	}
+	if isValuelessMatchBy(matchBy) || matchBy == matchBySetEquals {
+		return nil, fmt.Errorf("at_least and at_most are not supported by match_by '%s'", matchBy)
+	}
+	if count.atLeast >= 0 && count.atMost >= 0 && count.atLeast > count.atMost {
+		return nil, fmt.Errorf("at_least (%d) cannot be greater than at_most (%d) for field '%s'", count.atLeast, count.atMost, attr)
+	}
+	return &count, nil
+}
+
+// Returns the number of elements of the list or set value matching the filter values,
+// any of them or all of them depending on the filter.
+func countMatchingElements(s *schema.Schema, value interface{}, f commonFilter) int {
+	var elements []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		elements = v
+	case *schema.Set:
+		elements = v.List()
+	}
+	elemSchema := s.Elem.(*schema.Schema)
+	count := 0
+	for _, element := range elements {
+		matches := f.all
+		for _, filterValue := range f.values {
+			elementMatches := valueMatches(elemSchema, element, filterValue, f.matchBy)
+			if f.all {
+				matches = matches && elementMatches
+			} else {
+				matches = matches || elementMatches
+			}
+		}
+		if matches {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,89 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_elementCount(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name":             {Type: schema.TypeString},
+		"interface_metros": {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+		"tags":             {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "interface_metros": []interface{}{"DC", "DC", "SV"}, "tags": schema.NewSet(schema.HashString, []interface{}{"prod-a", "prod-b"})},
+		{"name": "dev-2", "interface_metros": []interface{}{"DC", "SV", "SV"}, "tags": schema.NewSet(schema.HashString, []interface{}{"prod-a"})},
+		{"name": "dev-3", "interface_metros": []interface{}{"DC", "DC", "DC"}, "tags": schema.NewSet(schema.HashString, []interface{}{"dev"})},
+		{"name": "dev-4"},
+	}
+	testCases := []struct {
+		name     string
+		filter   map[string]interface{}
+		expected []string
+	}{
+		{
+			"AtLeast",
+			map[string]interface{}{"attribute": "interface_metros", "values": []interface{}{"Ashburn"}, "match_by": "metro", "at_least": 2},
+			[]string{"dev-1", "dev-3"},
+		},
+		{
+			"AtMost",
+			map[string]interface{}{"attribute": "interface_metros", "values": []interface{}{"DC"}, "at_most": 1},
+			[]string{"dev-2", "dev-4"},
+		},
+		{
+			"Range",
+			map[string]interface{}{"attribute": "interface_metros", "values": []interface{}{"DC"}, "at_least": 2, "at_most": 2},
+			[]string{"dev-1"},
+		},
+		{
+			"AnyValueOfSet",
+			map[string]interface{}{"attribute": "tags", "values": []interface{}{"^prod-", "^dev$"}, "match_by": "re", "at_least": 2},
+			[]string{"dev-1"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{testCase.filter})
+			if err != nil {
+				t.Fatalf("expandFilters returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expected, names)
+		})
+	}
+}
+
+func TestExpandFilters_invalidElementCount(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+		"tags": {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	testCases := []struct {
+		filter   map[string]interface{}
+		expected string
+	}{
+		{
+			map[string]interface{}{"attribute": "name", "values": []interface{}{"a"}, "at_least": 1},
+			"at_least and at_most are not supported by field 'name' of type TypeString",
+		},
+		{
+			map[string]interface{}{"attribute": "tags", "match_by": "present", "at_most": 1},
+			"at_least and at_most are not supported by match_by 'present'",
+		},
+		{
+			map[string]interface{}{"attribute": "tags", "values": []interface{}{"a"}, "at_least": 3, "at_most": 2},
+			"at_least (3) cannot be greater than at_most (2) for field 'tags'",
+		},
+	}
+	for _, testCase := range testCases {
+		_, err := expandFilters(recordSchema, []interface{}{testCase.filter})
+		assert.EqualError(t, err, testCase.expected)
+	}
+}
@@ -0,0 +1,76 @@
+package datalist
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DerivedAttribute is a boolean record attribute computed from the other attributes of
+// the records, e.g. a `healthy` attribute combining their status, billing status and
+// alerts, which can then be filtered and sorted on like any other attribute.
+type DerivedAttribute struct {
+	// The filter expression, in the JSON syntax of `filter_expression`, which the
+	// records the attribute is true for match. It can only refer to the attributes of
+	// the record schema, not to other derived attributes.
+	Expression string
+
+	// The description of the attribute.
+	Description string
+}
+
+// Returns a copy of the record schema including the derived attributes.
+func withDerivedAttributes(recordSchema map[string]*schema.Schema, derived map[string]DerivedAttribute) map[string]*schema.Schema {
+	extended := make(map[string]*schema.Schema, len(recordSchema)+len(derived))
+	for attr, s := range recordSchema {
+		extended[attr] = s
+	}
+	for attr, d := range derived {
+		extended[attr] = &schema.Schema{Type: schema.TypeBool, Description: d.Description}
+	}
+	return extended
+}
+
+// Ensures that the derived attributes do not clash with the record attributes and that
+// their expressions are valid.
+func validateDerivedAttributes(recordSchema map[string]*schema.Schema, derived map[string]DerivedAttribute) error {
+	for attr, d := range derived {
+		if _, ok := recordSchema[attr]; ok {
+			return fmt.Errorf("derived attribute %q is already defined by the record schema", attr)
+		}
+		if _, err := expandFilterExpression(filterRecordSchema(recordSchema), d.Expression); err != nil {
+			return fmt.Errorf("invalid expression of derived attribute %q: %s", attr, err)
+		}
+	}
+	return nil
+}
+
+// derivedExpression is a derived attribute compiled for a read.
+type derivedExpression struct {
+	attribute  string
+	expression filterExpression
+}
+
+// Compiles the expressions of the derived attributes, in the order of their names.
+func compileDerivedAttributes(recordSchema map[string]*schema.Schema, derived map[string]DerivedAttribute) ([]derivedExpression, error) {
+	compiled := make([]derivedExpression, 0, len(derived))
+	for attr, d := range derived {
+		e, err := expandFilterExpression(recordSchema, d.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression of derived attribute %q: %s", attr, err)
+		}
+		compiled = append(compiled, derivedExpression{attribute: attr, expression: e})
+	}
+	sort.Slice(compiled, func(i, j int) bool {
+		return compiled[i].attribute < compiled[j].attribute
+	})
+	return compiled, nil
+}
+
+// Sets the derived attributes of the flattened record.
+func setDerivedAttributes(recordSchema map[string]*schema.Schema, record map[string]interface{}, derived []derivedExpression) {
+	for _, d := range derived {
+		record[d.attribute] = d.expression.matches(recordSchema, record)
+	}
+}
@@ -0,0 +1,83 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const healthyExpression = `{"and": [
+	{"attribute": "status", "values": ["ACTIVE"]},
+	{"attribute": "billing", "values": ["OK"]},
+	{"not": {"attribute": "alerts", "match_by": "present"}}
+]}`
+
+func derivedTestConfig() *ResourceConfig {
+	return newTestResourceConfig(map[string]*schema.Schema{
+		"name":    {Type: schema.TypeString},
+		"status":  {Type: schema.TypeString},
+		"billing": {Type: schema.TypeString},
+		"alerts":  {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+	}, []interface{}{
+		map[string]interface{}{"name": "healthy", "status": "ACTIVE", "billing": "OK", "alerts": []interface{}{}},
+		map[string]interface{}{"name": "inactive", "status": "FAILED", "billing": "OK", "alerts": []interface{}{}},
+		map[string]interface{}{"name": "unpaid", "status": "ACTIVE", "billing": "OVERDUE", "alerts": []interface{}{}},
+		map[string]interface{}{"name": "alerting", "status": "ACTIVE", "billing": "OK", "alerts": []interface{}{"high cpu"}},
+	}, func(config *ResourceConfig) {
+		config.DerivedAttributes = map[string]DerivedAttribute{
+			"healthy": {Expression: healthyExpression, Description: "Whether the device is active, paid for and free of alerts"},
+		}
+	})
+}
+
+func TestNewResource_derivedAttributes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expectedNames []interface{}
+	}{
+		{"Healthy", "true", []interface{}{"healthy"}},
+		{"Unhealthy", "false", []interface{}{"inactive", "unpaid", "alerting"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			resource := NewResource(derivedTestConfig())
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"attribute": "healthy", "values": []interface{}{testCase.value}},
+				},
+			})
+			// when
+			diags := resource.ReadContext(context.Background(), d, nil)
+			// then
+			assert.False(t, diags.HasError(), "Read does not return errors: %v", diags)
+			var names []interface{}
+			for _, device := range d.Get("devices").([]interface{}) {
+				device := device.(map[string]interface{})
+				names = append(names, device["name"])
+				assert.Equal(t, testCase.value == "true", device["healthy"], "The derived attribute is exposed")
+			}
+			assert.Equal(t, testCase.expectedNames, names, "Records are filtered on the derived attribute")
+		})
+	}
+}
+
+func TestNewResource_invalidDerivedAttributes(t *testing.T) {
+	testCases := map[string]map[string]DerivedAttribute{
+		"Clash":             {"status": {Expression: healthyExpression}},
+		"InvalidExpression": {"healthy": {Expression: `{"attribute": "unknown", "values": ["a"]}`}},
+		"ReferencesDerived": {"healthy": {Expression: healthyExpression}, "ok": {Expression: `{"attribute": "healthy", "values": ["true"]}`}},
+	}
+	for name, derived := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// given
+			config := derivedTestConfig()
+			config.DerivedAttributes = derived
+			// then
+			assert.Panics(t, func() { NewResource(config) }, "Invalid derived attributes are rejected")
+		})
+	}
+}
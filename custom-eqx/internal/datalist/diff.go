@@ -0,0 +1,82 @@
+package datalist
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// RecordsDiff lists the identifiers of the records added, removed and changed
+// between two snapshots of a data list, in ascending order.
+type RecordsDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// DiffRecords compares two snapshots of flattened records, keyed by their identifiers.
+// Primitive attributes are compared with the filter and sort comparison rules, so
+// floats are compared approximately, and other attributes are compared deeply.
+// Records without an identifier are ignored.
+func DiffRecords(recordSchema map[string]*schema.Schema, previous, current []map[string]interface{}) RecordsDiff {
+	previousByID := recordsByID(previous)
+	currentByID := recordsByID(current)
+
+	var diff RecordsDiff
+	for id, record := range currentByID {
+		previousRecord, ok := previousByID[id]
+		if !ok {
+			diff.Added = append(diff.Added, id)
+		} else if recordChanged(recordSchema, previousRecord, record) {
+			diff.Changed = append(diff.Changed, id)
+		}
+	}
+	for id := range previousByID {
+		if _, ok := currentByID[id]; !ok {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+func recordsByID(records []map[string]interface{}) map[string]map[string]interface{} {
+	byID := make(map[string]map[string]interface{}, len(records))
+	for _, record := range records {
+		if id := recordID(record); id != "" {
+			byID[id] = record
+		}
+	}
+	return byID
+}
+
+func recordChanged(recordSchema map[string]*schema.Schema, previous, current map[string]interface{}) bool {
+	attributes := make(map[string]struct{}, len(current))
+	for attr := range previous {
+		attributes[attr] = struct{}{}
+	}
+	for attr := range current {
+		attributes[attr] = struct{}{}
+	}
+	for attr := range attributes {
+		value1, value2 := previous[attr], current[attr]
+		if value1 == nil || value2 == nil {
+			if value1 != value2 {
+				return true
+			}
+			continue
+		}
+		s, ok := recordSchema[attr]
+		if ok && isPrimitiveType(s.Type) {
+			if compareValues(s, value1, value2) != 0 {
+				return true
+			}
+		} else if !reflect.DeepEqual(value1, value2) {
+			return true
+		}
+	}
+	return false
+}
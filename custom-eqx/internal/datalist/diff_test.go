@@ -0,0 +1,54 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRecords(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"id":        {Type: schema.TypeString},
+		"name":      {Type: schema.TypeString},
+		"bandwidth": {Type: schema.TypeFloat},
+		"tags":      {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	previous := []map[string]interface{}{
+		{"id": "dev-1", "name": "one", "bandwidth": 10.0, "tags": []interface{}{"a"}},
+		{"id": "dev-2", "name": "two", "bandwidth": 10.0, "tags": []interface{}{"a"}},
+		{"id": "dev-3", "name": "three", "bandwidth": 10.0, "tags": []interface{}{"a"}},
+		{"id": "dev-4", "name": "four", "bandwidth": 10.0, "tags": []interface{}{"a"}},
+		{"name": "no-id"},
+	}
+	current := []map[string]interface{}{
+		{"id": "dev-1", "name": "one", "bandwidth": 10.0000000001, "tags": []interface{}{"a"}},
+		{"id": "dev-2", "name": "two-renamed", "bandwidth": 10.0, "tags": []interface{}{"a"}},
+		{"id": "dev-3", "name": "three", "bandwidth": 10.0, "tags": []interface{}{"a", "b"}},
+		{"id": "dev-5", "name": "five", "bandwidth": 10.0, "tags": []interface{}{"a"}},
+		{"name": "other-no-id"},
+	}
+	// when
+	diff := DiffRecords(recordSchema, previous, current)
+	// then
+	assert.Equal(t, []string{"dev-5"}, diff.Added, "Records only in the current snapshot are added")
+	assert.Equal(t, []string{"dev-4"}, diff.Removed, "Records only in the previous snapshot are removed")
+	assert.Equal(t, []string{"dev-2", "dev-3"}, diff.Changed, "Records with different attributes are changed")
+}
+
+func TestDiffRecords_missingAttributes(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"uuid":   {Type: schema.TypeString},
+		"status": {Type: schema.TypeString},
+	}
+	previous := []map[string]interface{}{{"uuid": "dev-1"}, {"uuid": "dev-2", "status": "PROVISIONED"}}
+	current := []map[string]interface{}{{"uuid": "dev-1", "status": "PROVISIONED"}, {"uuid": "dev-2", "status": "PROVISIONED"}}
+	// when
+	diff := DiffRecords(recordSchema, previous, current)
+	// then
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Equal(t, []string{"dev-1"}, diff.Changed, "Attribute set in one snapshot only is a change")
+}
@@ -0,0 +1,42 @@
+package datalist
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Returns the records without those repeating the value of the attribute of an
+// earlier record. Records without a value for the attribute are all kept.
+func distinctRecords(records []map[string]interface{}, attribute string) []map[string]interface{} {
+	seen := make(map[string]bool, len(records))
+	distinct := records[:0:0]
+	for _, record := range records {
+		value, ok := record[attribute]
+		if ok && value != nil {
+			key := distinctKey(value)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		distinct = append(distinct, record)
+	}
+	return distinct
+}
+
+// Returns the key identifying the value of the attribute. Sets are keyed by their
+// elements in a stable order, as they are not ordered.
+func distinctKey(value interface{}) string {
+	set, ok := value.(*schema.Set)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	elements := make([]string, set.Len())
+	for i, element := range set.List() {
+		elements[i] = fmt.Sprint(element)
+	}
+	sort.Strings(elements)
+	return fmt.Sprint(elements)
+}
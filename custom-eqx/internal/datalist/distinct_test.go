@@ -0,0 +1,67 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResource_distinctBy(t *testing.T) {
+	// given
+	pages := [][]interface{}{
+		{
+			map[string]interface{}{"uuid": "dev-1", "name": "first"},
+			map[string]interface{}{"uuid": "dev-2", "name": "second"},
+		},
+		{
+			// The first page shifted while loading, repeating its last record
+			map[string]interface{}{"uuid": "dev-2", "name": "second-again"},
+			map[string]interface{}{"uuid": "dev-3", "name": "third"},
+		},
+	}
+	resource := newTestResource(map[string]*schema.Schema{
+		"uuid": {Type: schema.TypeString},
+		"name": {Type: schema.TypeString},
+	}, nil, func(config *ResourceConfig) {
+		withTestPages(config, 2, func(ctx context.Context, meta interface{}, extra map[string]interface{}, offset, limit int) ([]interface{}, int, error) {
+			if page := offset / limit; page < len(pages) {
+				return pages[page], -1, nil
+			}
+			return nil, -1, nil
+		})
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"distinct_by": "uuid",
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	var names []string
+	for _, device := range d.Get("devices").([]interface{}) {
+		names = append(names, device.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, names, "First occurrence of duplicate records is kept")
+}
+
+func TestDistinctRecords(t *testing.T) {
+	// given
+	records := []map[string]interface{}{
+		{"name": "a", "cores": 2},
+		{"name": "b", "cores": 4},
+		{"name": "c", "cores": 2},
+		{"name": "d"},
+		{"name": "e"},
+	}
+	// when
+	distinct := distinctRecords(records, "cores")
+	// then
+	var names []string
+	for _, record := range distinct {
+		names = append(names, record["name"].(string))
+	}
+	assert.Equal(t, []string{"a", "b", "d", "e"}, names, "Records without the attribute are kept")
+	assert.Len(t, records, 5, "Input records are not modified")
+}
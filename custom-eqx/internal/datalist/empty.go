@@ -0,0 +1,60 @@
+package datalist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Returns a warning listing the filters applied by the data source, or nil when it
+// applies none, in which case the lack of results is not caused by filtering.
+func emptyResultsWarning(d *schema.ResourceData) diag.Diagnostics {
+	var applied []string
+	if v, ok := d.GetOk("filter"); ok {
+		for _, raw := range v.(*schema.Set).List() {
+			applied = append(applied, describeFilter(raw.(map[string]interface{})))
+		}
+		sort.Strings(applied)
+	}
+	for _, attr := range []string{"filter_expression", "expression"} {
+		if v, ok := d.GetOk(attr); ok {
+			applied = append(applied, fmt.Sprintf("%s: %s", attr, v))
+		}
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "No results match the filters",
+		Detail:   fmt.Sprintf("Applied filters:\n  %s\nSet warn_on_empty to false if the lookup is optional.", strings.Join(applied, "\n  ")),
+	}}
+}
+
+func describeFilter(f map[string]interface{}) string {
+	// Filters of expressions may omit the default mode
+	matchBy, _ := f["match_by"].(string)
+	if matchBy == "" {
+		matchBy = "in"
+	}
+	description := fmt.Sprintf("%s %s", f["attribute"], matchBy)
+	if variable, _ := f["variable"].(string); variable != "" {
+		return fmt.Sprintf("%s variable %s", description, variable)
+	}
+	if isValuelessMatchBy(matchBy) {
+		return description
+	}
+	var values []string
+	rawValues, _ := f["values"].([]interface{})
+	for _, v := range rawValues {
+		values = append(values, fmt.Sprintf("%q", v))
+	}
+	joiner := " or "
+	if all, _ := f["all"].(bool); all || matchBy == "str_between" {
+		joiner = " and "
+	}
+	return fmt.Sprintf("%s %s", description, strings.Join(values, joiner))
+}
@@ -0,0 +1,85 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEmptyResource() *schema.Resource {
+	return newTestResource(map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+	}, []interface{}{
+		map[string]interface{}{"name": "dev-1", "metro_code": "SV"},
+		map[string]interface{}{"name": "dev-2", "metro_code": "DC"},
+	}, nil)
+}
+
+func TestNewResource_warnOnEmpty(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      map[string]interface{}
+		expected diag.Diagnostics
+	}{
+		{
+			"NoMatch",
+			map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"AM", "LD"}},
+					map[string]interface{}{"attribute": "name", "match_by": "present"},
+				},
+				"filter_expression": `{"attribute": "name", "values": ["dev"], "match_by": "substring"}`,
+			},
+			diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  "No results match the filters",
+				Detail: "Applied filters:\n" +
+					"  metro_code in \"AM\" or \"LD\"\n" +
+					"  name present\n" +
+					"  filter_expression: {\"attribute\": \"name\", \"values\": [\"dev\"], \"match_by\": \"substring\"}\n" +
+					"Set warn_on_empty to false if the lookup is optional.",
+			}},
+		},
+		{
+			"Match",
+			map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"SV"}},
+				},
+			},
+			nil,
+		},
+		{
+			"Suppressed",
+			map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"AM"}},
+				},
+				"warn_on_empty": false,
+			},
+			nil,
+		},
+		{
+			"NoFilters",
+			map[string]interface{}{
+				"limit": 1,
+			},
+			nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resource := testEmptyResource()
+			d := schema.TestResourceDataRaw(t, resource.Schema, testCase.raw)
+
+			diags := resource.ReadContext(context.Background(), d, nil)
+
+			assert.Equal(t, testCase.expected, diags)
+		})
+	}
+}
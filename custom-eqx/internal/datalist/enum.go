@@ -0,0 +1,91 @@
+package datalist
+
+import (
+	"strings"
+)
+
+// StatusAliases is an alias table for the status attributes of Equinix resources,
+// mapping common synonyms to the statuses returned by the API.
+var StatusAliases = map[string]string{
+	"active":      "PROVISIONED",
+	"ready":       "PROVISIONED",
+	"pending":     "PROVISIONING",
+	"creating":    "PROVISIONING",
+	"deleting":    "DEPROVISIONING",
+	"deleted":     "DEPROVISIONED",
+	"terminated":  "DEPROVISIONED",
+	"failed":      "FAILED",
+	"error":       "FAILED",
+	"stopped":     "SUSPENDED",
+	"unavailable": "SUSPENDED",
+}
+
+// enumFilterValue is the filter value of the enum match mode. Both the filter value
+// and the record values are normalized through the alias table of the attribute.
+type enumFilterValue struct {
+	value   string
+	aliases map[string]string
+}
+
+func newEnumFilterValue(value string, aliases map[string]string) enumFilterValue {
+	return enumFilterValue{value: normalizeEnumValue(value, aliases), aliases: aliases}
+}
+
+func (v enumFilterValue) matches(value string) bool {
+	return normalizeEnumValue(value, v.aliases) == v.value
+}
+
+// enumSetFilterValue is the filter value of the not_in_enum match mode: the set of
+// known values of the enum, lowercased.
+type enumSetFilterValue map[string]struct{}
+
+func newEnumSetFilterValue(values []interface{}) enumSetFilterValue {
+	set := make(enumSetFilterValue, len(values))
+	for _, value := range values {
+		set[strings.ToLower(value.(string))] = struct{}{}
+	}
+	return set
+}
+
+func (s enumSetFilterValue) contains(value string) bool {
+	_, ok := s[strings.ToLower(value)]
+	return ok
+}
+
+// Normalizes the value to its lowercased canonical form. The alias table must have
+// lowercase keys.
+func normalizeEnumValue(value string, aliases map[string]string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if canonical, ok := aliases[normalized]; ok {
+		return strings.ToLower(canonical)
+	}
+	return normalized
+}
+
+// Returns a copy of the alias table with lowercase keys.
+func lowercaseEnumAliases(aliases map[string]string) map[string]string {
+	lowercased := make(map[string]string, len(aliases))
+	for alias, canonical := range aliases {
+		lowercased[strings.ToLower(alias)] = canonical
+	}
+	return lowercased
+}
+
+// Applies the per-attribute alias tables to the enum filters of the expression.
+func (e filterExpression) setEnumAliases(enumAliases map[string]map[string]string) {
+	for _, child := range e.children {
+		child.setEnumAliases(enumAliases)
+	}
+	if e.filter == nil || e.filter.matchBy != "enum" {
+		return
+	}
+	aliases := lowercaseEnumAliases(enumAliases[e.filter.attribute])
+	for i, value := range e.filter.values {
+		if v, ok := value.(transformedFilterValue); ok {
+			v.value = newEnumFilterValue(v.value.(enumFilterValue).value, aliases)
+			e.filter.values[i] = v
+			continue
+		}
+		e.filter.values[i] = newEnumFilterValue(value.(enumFilterValue).value, aliases)
+	}
+}
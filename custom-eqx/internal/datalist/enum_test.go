@@ -0,0 +1,98 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEnumValue(t *testing.T) {
+	aliases := lowercaseEnumAliases(map[string]string{"Active": "PROVISIONED"})
+
+	assert.Equal(t, "provisioned", normalizeEnumValue("active", aliases))
+	assert.Equal(t, "provisioned", normalizeEnumValue(" ACTIVE ", aliases))
+	assert.Equal(t, "provisioned", normalizeEnumValue("Provisioned", aliases))
+	assert.Equal(t, "failed", normalizeEnumValue("FAILED", aliases))
+}
+
+func TestNewResource_enumAliases(t *testing.T) {
+	records := []interface{}{
+		map[string]interface{}{"name": "dev-1", "status": "PROVISIONED", "type": "ROUTER"},
+		map[string]interface{}{"name": "dev-2", "status": "provisioned", "type": "firewall"},
+		map[string]interface{}{"name": "dev-3", "status": "FAILED", "type": "Router"},
+	}
+	testCases := []struct {
+		name          string
+		attribute     string
+		value         string
+		expectedNames []string
+	}{
+		{"Alias", "status", "active", []string{"dev-1", "dev-2"}},
+		{"Canonical", "status", "Provisioned", []string{"dev-1", "dev-2"}},
+		{"NotAliased", "status", "failed", []string{"dev-3"}},
+		{"NoAliasTable", "type", "router", []string{"dev-1", "dev-3"}},
+		{"NoAliasTableNoMatch", "type", "active", nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resource := newTestResource(map[string]*schema.Schema{
+				"name":   {Type: schema.TypeString},
+				"status": {Type: schema.TypeString},
+				"type":   {Type: schema.TypeString},
+			}, records, func(config *ResourceConfig) {
+				config.EnumAliases = map[string]map[string]string{"status": StatusAliases}
+			})
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{
+						"attribute": testCase.attribute,
+						"values":    []interface{}{testCase.value},
+						"match_by":  "enum",
+					},
+				},
+			})
+
+			diags := resource.ReadContext(context.Background(), d, nil)
+
+			assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+			var names []string
+			for _, device := range d.Get("devices").([]interface{}) {
+				names = append(names, device.(map[string]interface{})["name"].(string))
+			}
+			assert.Equal(t, testCase.expectedNames, names)
+		})
+	}
+}
+
+func TestApplyFilters_notInEnum(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"name":   {Type: schema.TypeString},
+		"status": {Type: schema.TypeString},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "status": "PROVISIONED"},
+		{"name": "dev-2", "status": "provisioning"},
+		{"name": "dev-3", "status": "QUARANTINED"},
+		{"name": "dev-4", "status": "Deprovisioned"},
+		{"name": "dev-5", "status": ""},
+	}
+	filters, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "status",
+			"values":    []interface{}{"PROVISIONED", "PROVISIONING", "DEPROVISIONED"},
+			"match_by":  "not_in_enum",
+		},
+	})
+	// when
+	var names []string
+	for _, record := range applyFilters(recordSchema, records, filters) {
+		names = append(names, record["name"].(string))
+	}
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dev-3", "dev-5"}, names, "Only values outside of the enum match, case-insensitively")
+}
@@ -0,0 +1,88 @@
+package datalist
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func explanationsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The filters satisfied by each result when explain is true, in the same order as the results",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"matched_filters": {
+					Type:        schema.TypeList,
+					Computed:    true,
+					Description: "The descriptions of the filters the result satisfied, e.g. name in \"a\" or \"b\"",
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+// Returns the descriptions of the leaves of the expression satisfied by the record. The
+// leaves of `not` nodes are reported as a whole when the `not` node is satisfied, since
+// the record satisfies it by not matching them.
+func (e filterExpression) explain(recordSchema map[string]*schema.Schema, record map[string]interface{}) []string {
+	if e.op == expressionAnd || e.op == expressionOr {
+		var matched []string
+		for _, child := range e.children {
+			matched = append(matched, child.explain(recordSchema, record)...)
+		}
+		return matched
+	}
+	if e.matches(recordSchema, record) {
+		return []string{e.describe()}
+	}
+	return nil
+}
+
+// Returns a human readable form of the expression.
+func (e filterExpression) describe() string {
+	switch e.op {
+	case expressionAnd, expressionOr:
+		description := ""
+		for i, child := range e.children {
+			if i > 0 {
+				description += " " + e.op + " "
+			}
+			description += "(" + child.describe() + ")"
+		}
+		return description
+	case expressionNot:
+		return fmt.Sprintf("not (%s)", e.children[0].describe())
+	}
+	if e.ratio != nil {
+		return fmt.Sprintf("ratio of %s to %s %s %v", e.ratio.numerator, e.ratio.denominator, e.ratio.matchBy, e.ratio.threshold)
+	}
+	if e.capture != nil {
+		if e.capture.equalsAttribute != "" {
+			return fmt.Sprintf("capture from %s equals attribute %s", e.capture.attribute, e.capture.equalsAttribute)
+		}
+		return fmt.Sprintf("capture from %s equals %q", e.capture.attribute, e.capture.equals)
+	}
+	if e.predicate != nil {
+		return "expression"
+	}
+	return e.filter.description
+}
+
+// Lists the filters satisfied by each record, in the format of the explanations attribute.
+// The filters pushed down to the API, which all of the loaded records satisfy, are
+// listed first.
+func explainRecords(recordSchema map[string]*schema.Schema, records []map[string]interface{}, expression filterExpression, pushed []commonFilter) []interface{} {
+	explanations := make([]interface{}, len(records))
+	for i, record := range records {
+		matched := []string{}
+		for _, f := range pushed {
+			matched = append(matched, f.description)
+		}
+		matched = append(matched, expression.explain(recordSchema, record)...)
+		explanations[i] = map[string]interface{}{"matched_filters": matched}
+	}
+	return explanations
+}
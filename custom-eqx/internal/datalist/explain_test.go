@@ -0,0 +1,112 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterExpression_explain(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+		"status":     {Type: schema.TypeString},
+	}
+	expression, err := expandFilterExpression(recordSchema, `{"or": [
+		{"attribute": "metro_code", "values": ["SV"]},
+		{"attribute": "name", "values": ["dev"], "match_by": "substring"},
+		{"not": {"attribute": "status", "values": ["DEPROVISIONED"]}}
+	]}`)
+	assert.Nil(t, err, "Expression is valid")
+	records := []map[string]interface{}{
+		{"name": "dev-1", "metro_code": "SV", "status": "PROVISIONED"},
+		{"name": "prod-1", "metro_code": "SV", "status": "DEPROVISIONED"},
+		{"name": "prod-2", "metro_code": "DC", "status": "DEPROVISIONED"},
+	}
+	// when
+	explanations := explainRecords(recordSchema, records, expression, nil)
+	// then
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"matched_filters": []string{`metro_code in "SV"`, `name substring "dev"`, `not (status in "DEPROVISIONED")`}},
+		map[string]interface{}{"matched_filters": []string{`metro_code in "SV"`}},
+		map[string]interface{}{"matched_filters": []string{}},
+	}, explanations, "Explanations list the filters satisfied by each record")
+}
+
+func TestNewResource_explain(t *testing.T) {
+	// given
+	resource := newTestResource(map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+	}, []interface{}{
+		map[string]interface{}{"name": "dev-1", "metro_code": "SV"},
+		map[string]interface{}{"name": "dev-2", "metro_code": "DC"},
+		map[string]interface{}{"name": "prod-1", "metro_code": "SV"},
+	}, nil)
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"SV"}},
+		},
+		"filter_expression": `{"or": [{"attribute": "name", "values": ["dev"], "match_by": "substring"}, {"attribute": "name", "values": ["prod-1"]}]}`,
+		"explain":           true,
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "Read does not return errors")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"matched_filters": []interface{}{`metro_code in "SV"`, `name substring "dev"`}},
+		map[string]interface{}{"matched_filters": []interface{}{`metro_code in "SV"`, `name in "prod-1"`}},
+	}, d.Get("explanations"), "Explanations list the filters satisfied by each result")
+}
+
+func TestNewResource_explainDisabled(t *testing.T) {
+	// given
+	resource := newTestResource(map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+	}, []interface{}{map[string]interface{}{"name": "dev-1"}}, nil)
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "name", "values": []interface{}{"dev-1"}},
+		},
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "Read does not return errors")
+	assert.Empty(t, d.Get("explanations"), "Explanations are empty unless explain is set")
+}
+
+func TestNewResource_explainPushedDown(t *testing.T) {
+	// given
+	resource := newTestResource(map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+	}, nil, func(config *ResourceConfig) {
+		config.GetRecords = func(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+			// The API applies the metroCode query parameter
+			return []interface{}{
+				map[string]interface{}{"name": "dev-1", "metro_code": "SV"},
+				map[string]interface{}{"name": "prod-1", "metro_code": "SV"},
+			}, nil
+		}
+		config.QueryParameters = map[string]string{"metro_code": "metroCode"}
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"SV"}},
+			map[string]interface{}{"attribute": "name", "values": []interface{}{"dev"}, "match_by": "substring"},
+		},
+		"explain": true,
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "Read does not return errors")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"matched_filters": []interface{}{`metro_code in "SV"`, `name substring "dev"`}},
+	}, d.Get("explanations"), "Explanations list the filters pushed down to the API")
+}
@@ -0,0 +1,148 @@
+package datalist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+func exportSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Writes the results to a file when the data source is read",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"format": {
+					Type:         schema.TypeString,
+					Description:  "The format of the file. One of: csv, json",
+					Required:     true,
+					ValidateFunc: validation.StringInSlice([]string{exportFormatCSV, exportFormatJSON}, false),
+				},
+				"path": {
+					Type:         schema.TypeString,
+					Description:  "The path of the file, which is overwritten",
+					Required:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				"attributes": {
+					Type:        schema.TypeList,
+					Description: "The attributes to export, in order. Defaults to all of the attributes, in alphabetical order",
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+type exportConfig struct {
+	format     string
+	path       string
+	attributes []string
+}
+
+func expandExport(recordSchema map[string]*schema.Schema, raw map[string]interface{}) (exportConfig, error) {
+	e := exportConfig{format: raw["format"].(string), path: raw["path"].(string)}
+	if rawAttributes, ok := raw["attributes"].([]interface{}); ok {
+		for _, rawAttribute := range rawAttributes {
+			attr := rawAttribute.(string)
+			if _, ok := recordSchema[attr]; !ok {
+				return exportConfig{}, fmt.Errorf("export attribute '%s' does not exist in record schema", attr)
+			}
+			e.attributes = append(e.attributes, attr)
+		}
+	}
+	if len(e.attributes) == 0 {
+		for attr := range recordSchema {
+			e.attributes = append(e.attributes, attr)
+		}
+		sort.Strings(e.attributes)
+	}
+	return e, nil
+}
+
+// Writes the selected attributes of the records to the export file.
+func exportRecords(e exportConfig, records []map[string]interface{}) error {
+	var content []byte
+	var err error
+	switch e.format {
+	case exportFormatCSV:
+		content, err = exportCSV(e.attributes, records)
+	case exportFormatJSON:
+		content, err = exportJSON(e.attributes, records)
+	default:
+		err = fmt.Errorf("unsupported export format: %q", e.format)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to export results: %s", err)
+	}
+	if err := os.WriteFile(e.path, content, 0o644); err != nil {
+		return fmt.Errorf("unable to export results: %s", err)
+	}
+	return nil
+}
+
+func exportJSON(attributes []string, records []map[string]interface{}) ([]byte, error) {
+	selected := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		selected[i] = make(map[string]interface{}, len(attributes))
+		for _, attr := range attributes {
+			selected[i][attr] = exportValue(record[attr])
+		}
+	}
+	return json.MarshalIndent(selected, "", "  ")
+}
+
+// Writes a header row with the attribute names, followed by a row per record. Values
+// of lists, sets and maps are JSON encoded.
+func exportCSV(attributes []string, records []map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(attributes); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		row := make([]string, len(attributes))
+		for i, attr := range attributes {
+			switch v := exportValue(record[attr]).(type) {
+			case nil:
+			case string:
+				row[i] = v
+			case bool, int, float64:
+				row[i] = fmt.Sprint(v)
+			default:
+				encoded, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				row[i] = string(encoded)
+			}
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// Converts the sets of flattened records to lists, which can be encoded.
+func exportValue(value interface{}) interface{} {
+	if set, ok := value.(*schema.Set); ok {
+		return set.List()
+	}
+	return value
+}
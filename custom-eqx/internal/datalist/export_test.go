@@ -0,0 +1,89 @@
+package datalist
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testExportResource() *schema.Resource {
+	return newTestResource(map[string]*schema.Schema{
+		"name":  {Type: schema.TypeString},
+		"cores": {Type: schema.TypeInt},
+		"tags":  {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+	}, []interface{}{
+		map[string]interface{}{"name": "dev-2", "cores": 4, "tags": []interface{}{"a", "b"}},
+		map[string]interface{}{"name": "dev, \"1\"", "cores": 2, "tags": []interface{}{}},
+		map[string]interface{}{"name": "dev-3", "cores": 8, "tags": []interface{}{"c"}},
+	}, nil)
+}
+
+func readExport(t *testing.T, raw map[string]interface{}) string {
+	path := filepath.Join(t.TempDir(), "export")
+	raw["export"].([]interface{})[0].(map[string]interface{})["path"] = path
+	resource := testExportResource()
+	d := schema.TestResourceDataRaw(t, resource.Schema, raw)
+	diags := resource.ReadContext(context.Background(), d, nil)
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestNewResource_exportCSV(t *testing.T) {
+	content := readExport(t, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "cores", "values": []interface{}{"8"}, "match_by": "less_than"},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"attribute": "cores"},
+		},
+		"export": []interface{}{
+			map[string]interface{}{"format": "csv", "attributes": []interface{}{"name", "tags"}},
+		},
+	})
+
+	assert.Equal(t, "name,tags\n\"dev, \"\"1\"\"\",[]\ndev-2,\"[\"\"a\"\",\"\"b\"\"]\"\n", content, "Filtered and sorted records are exported")
+}
+
+func TestNewResource_exportJSON(t *testing.T) {
+	content := readExport(t, map[string]interface{}{
+		"limit": 1,
+		"export": []interface{}{
+			map[string]interface{}{"format": "json"},
+		},
+	})
+
+	assert.JSONEq(t, `[{"cores": 4, "name": "dev-2", "tags": ["a", "b"]}]`, content, "All attributes are exported by default")
+}
+
+func TestNewResource_exportErrors(t *testing.T) {
+	testCases := map[string]struct {
+		export   map[string]interface{}
+		expected string
+	}{
+		"missing directory": {
+			export:   map[string]interface{}{"format": "json", "path": filepath.Join(t.TempDir(), "missing", "export.json")},
+			expected: "unable to export results: open ",
+		},
+		"unknown attribute": {
+			export:   map[string]interface{}{"format": "csv", "path": filepath.Join(t.TempDir(), "export.csv"), "attributes": []interface{}{"memory"}},
+			expected: "export attribute 'memory' does not exist in record schema",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resource := testExportResource()
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+				"export": []interface{}{testCase.export},
+			})
+			diags := resource.ReadContext(context.Background(), d, nil)
+			assert.True(t, diags.HasError(), "read returns an error")
+			assert.Contains(t, diags[0].Summary, testCase.expected)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package datalist
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	expressionAnd = "and"
+	expressionOr  = "or"
+	expressionNot = "not"
+)
+
+// filterExpression is a node of a boolean expression tree over filters. Leaf nodes
+// carry a single filter, ratio filter, capture filter or predicate compiled by an
+// ExpressionLanguage, while `and`, `or` and `not` nodes combine the results of their
+// children.
+type filterExpression struct {
+	op        string
+	filter    *commonFilter
+	ratio     *ratioFilter
+	capture   *captureFilter
+	predicate RecordPredicate
+	children  []filterExpression
+}
+
+func filterExpressionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  "A JSON encoded boolean expression over filters. Nodes are either a filter object with the same keys as the `filter` block, or an object with a single `and`, `or` (list of nodes) or `not` (single node) key. A `ratio` key compares the ratio of two numeric attributes, e.g. {\"ratio\": {\"numerator\": \"used\", \"denominator\": \"provisioned\", \"match_by\": \"greater_than\", \"value\": 0.8}}; ratios with a zero denominator do not match unless `on_zero_denominator` is \"zero\". A `capture` key compares a group captured from a string attribute with a literal or another attribute, e.g. {\"capture\": {\"attribute\": \"port_name\", \"pattern\": \"^([A-Z]{2})\\\\d+-\", \"equals_attribute\": \"metro_code\"}}; the group is 1 unless `group` gives another index or name, and `equals` compares with a literal instead. The expression is joined with an AND with any `filter` blocks",
+		Optional:     true,
+		ValidateFunc: validateFilterExpression,
+	}
+}
+
+func validateFilterExpression(v interface{}, k string) (ws []string, errors []error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(v.(string)), &raw); err != nil {
+		errors = append(errors, fmt.Errorf("%q contains an invalid JSON: %s", k, err))
+	}
+	return
+}
+
+// Compiles a flat list of filters into an expression tree which joins all of them with an AND.
+func compileFilters(filters []commonFilter) filterExpression {
+	children := make([]filterExpression, len(filters))
+	for i := range filters {
+		children[i] = filterExpression{filter: &filters[i]}
+	}
+	return filterExpression{op: expressionAnd, children: children}
+}
+
+// Parses a JSON encoded filter expression into an expression tree. Leaf filters are validated
+// against the record schema the same way as the `filter` blocks.
+func expandFilterExpression(recordSchema map[string]*schema.Schema, rawExpression string) (filterExpression, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(rawExpression), &raw); err != nil {
+		return filterExpression{}, fmt.Errorf("unable to parse filter expression: %s", err)
+	}
+	return expandFilterExpressionNode(recordSchema, raw)
+}
+
+func expandFilterExpressionNode(recordSchema map[string]*schema.Schema, raw interface{}) (filterExpression, error) {
+	node, ok := raw.(map[string]interface{})
+	if !ok {
+		return filterExpression{}, fmt.Errorf("filter expression node must be an object, got: %v", raw)
+	}
+
+	if _, ok := node["attribute"]; ok {
+		return expandFilterExpressionLeaf(recordSchema, node)
+	}
+
+	if len(node) != 1 {
+		return filterExpression{}, fmt.Errorf("filter expression node must have exactly one of %q, %q, %q, %q or %q keys", expressionAnd, expressionOr, expressionNot, expressionRatio, expressionCapture)
+	}
+
+	for op, rawChildren := range node {
+		switch op {
+		case expressionAnd, expressionOr:
+			list, ok := rawChildren.([]interface{})
+			if !ok {
+				return filterExpression{}, fmt.Errorf("%q filter expression node must be a list", op)
+			}
+			children := make([]filterExpression, len(list))
+			for i := range list {
+				child, err := expandFilterExpressionNode(recordSchema, list[i])
+				if err != nil {
+					return filterExpression{}, err
+				}
+				children[i] = child
+			}
+			return filterExpression{op: op, children: children}, nil
+		case expressionNot:
+			child, err := expandFilterExpressionNode(recordSchema, rawChildren)
+			if err != nil {
+				return filterExpression{}, err
+			}
+			return filterExpression{op: op, children: []filterExpression{child}}, nil
+		case expressionRatio:
+			ratio, err := expandRatioFilter(recordSchema, rawChildren)
+			if err != nil {
+				return filterExpression{}, err
+			}
+			return filterExpression{ratio: ratio}, nil
+		case expressionCapture:
+			capture, err := expandCaptureFilter(recordSchema, rawChildren)
+			if err != nil {
+				return filterExpression{}, err
+			}
+			return filterExpression{capture: capture}, nil
+		default:
+			return filterExpression{}, fmt.Errorf("unsupported filter expression operator: %q", op)
+		}
+	}
+
+	panic("unreachable")
+}
+
+func expandFilterExpressionLeaf(recordSchema map[string]*schema.Schema, node map[string]interface{}) (filterExpression, error) {
+	rawFilter := map[string]interface{}{}
+	for k, v := range node {
+		switch k {
+		case "attribute", "match_by":
+			s, ok := v.(string)
+			if !ok {
+				return filterExpression{}, fmt.Errorf("filter expression key %q must be a string", k)
+			}
+			rawFilter[k] = s
+		case "all", "ignore_key_case":
+			b, ok := v.(bool)
+			if !ok {
+				return filterExpression{}, fmt.Errorf("filter expression key %q must be a boolean", k)
+			}
+			rawFilter[k] = b
+		case "tolerance":
+			f, ok := v.(float64)
+			if !ok {
+				return filterExpression{}, fmt.Errorf("filter expression key %q must be a number", k)
+			}
+			rawFilter[k] = f
+		case "max_distance":
+			f, ok := v.(float64)
+			if !ok || f != float64(int(f)) {
+				return filterExpression{}, fmt.Errorf("filter expression key %q must be an integer", k)
+			}
+			rawFilter[k] = int(f)
+		case "values":
+			list, ok := v.([]interface{})
+			if !ok {
+				return filterExpression{}, fmt.Errorf("filter expression key %q must be a list", k)
+			}
+			values := make([]interface{}, len(list))
+			for i := range list {
+				values[i] = formatFilterValue(list[i])
+			}
+			rawFilter[k] = values
+		default:
+			return filterExpression{}, fmt.Errorf("unsupported filter expression key: %q", k)
+		}
+	}
+	if _, ok := rawFilter["values"]; !ok && !isValuelessMatchBy(fmt.Sprint(rawFilter["match_by"])) {
+		return filterExpression{}, fmt.Errorf("filter expression for attribute %q is missing values", rawFilter["attribute"])
+	}
+
+	filters, err := expandFilters(recordSchema, []interface{}{rawFilter})
+	if err != nil {
+		return filterExpression{}, err
+	}
+	return filterExpression{filter: &filters[0]}, nil
+}
+
+func (e filterExpression) matches(recordSchema map[string]*schema.Schema, record map[string]interface{}) bool {
+	switch e.op {
+	case expressionAnd:
+		for _, child := range e.children {
+			if !child.matches(recordSchema, record) {
+				return false
+			}
+		}
+		return true
+	case expressionOr:
+		for _, child := range e.children {
+			if child.matches(recordSchema, record) {
+				return true
+			}
+		}
+		return false
+	case expressionNot:
+		return !e.children[0].matches(recordSchema, record)
+	}
+	if e.ratio != nil {
+		return e.ratio.matches(record)
+	}
+	if e.capture != nil {
+		return e.capture.matches(record)
+	}
+	if e.predicate != nil {
+		return e.predicate(record)
+	}
+	return filterMatches(recordSchema, record, *e.filter)
+}
+
+func applyFilterExpression(recordSchema map[string]*schema.Schema, records []map[string]interface{}, expression filterExpression) []map[string]interface{} {
+	var filteredRecords []map[string]interface{}
+	for _, record := range records {
+		if expression.matches(recordSchema, record) {
+			filteredRecords = append(filteredRecords, record)
+		}
+	}
+	return filteredRecords
+}
@@ -0,0 +1,106 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandFilterExpression(t *testing.T) {
+	// (memory = 8192 or vcpus = 2) and not regions contains ams1
+	rawExpression := `{
+		"and": [
+			{"or": [
+				{"attribute": "memory", "values": [8192]},
+				{"attribute": "vcpus", "values": ["2"]}
+			]},
+			{"not": {"attribute": "regions", "values": ["ams1"]}}
+		]
+	}`
+
+	expression, err := expandFilterExpression(sizesTestSchema(), rawExpression)
+	if err != nil {
+		t.Fatalf("expandFilterExpression returned error: %s", err)
+	}
+
+	assert.Equal(t, expressionAnd, expression.op)
+	assert.Len(t, expression.children, 2)
+	assert.Equal(t, expressionOr, expression.children[0].op)
+	assert.Equal(t, expressionNot, expression.children[1].op)
+	assert.Equal(t, []interface{}{8192}, expression.children[0].children[0].filter.values)
+
+	sizes := applyFilterExpression(sizesTestSchema(), sizesTestData(), expression)
+	var slugs []string
+	for _, size := range sizes {
+		slugs = append(slugs, size["slug"].(string))
+	}
+	assert.Equal(t, []string{"s-2vcpu-2gb"}, slugs)
+}
+
+func TestExpandFilterExpression_invalid(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+	}{
+		{"NotJSON", `{"and": [`},
+		{"NotObject", `["memory"]`},
+		{"UnknownOperator", `{"xor": []}`},
+		{"MultipleOperators", `{"and": [], "or": []}`},
+		{"UnknownAttribute", `{"attribute": "foo", "values": ["bar"]}`},
+		{"MissingValues", `{"attribute": "slug"}`},
+		{"InvalidValue", `{"not": {"attribute": "memory", "values": ["lots"]}}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := expandFilterExpression(sizesTestSchema(), testCase.expression)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestApplyFilterExpression(t *testing.T) {
+	memory := commonFilter{attribute: "memory", values: []interface{}{8192}, matchBy: "in"}
+	vcpus := commonFilter{attribute: "vcpus", values: []interface{}{2}, matchBy: "in"}
+	available := commonFilter{attribute: "available", values: []interface{}{true}, matchBy: "in"}
+
+	// (A or B) and not C
+	expression := filterExpression{
+		op: expressionAnd,
+		children: []filterExpression{
+			{
+				op: expressionOr,
+				children: []filterExpression{
+					{filter: &memory},
+					{filter: &vcpus},
+				},
+			},
+			{
+				op:       expressionNot,
+				children: []filterExpression{{filter: &available}},
+			},
+		},
+	}
+
+	sizes := applyFilterExpression(sizesTestSchema(), sizesTestData(), expression)
+	var slugs []string
+	for _, size := range sizes {
+		slugs = append(slugs, size["slug"].(string))
+	}
+	assert.Equal(t, []string{"s-2vcpu-2gb", "m-1vcpu-8gb"}, slugs)
+}
+
+func TestCompileFilters(t *testing.T) {
+	filters := []commonFilter{
+		{attribute: "memory", values: []interface{}{8192}, matchBy: "in"},
+		{attribute: "available", values: []interface{}{true}, matchBy: "in"},
+	}
+
+	expression := compileFilters(filters)
+
+	assert.Equal(t, expressionAnd, expression.op)
+	assert.Len(t, expression.children, 2)
+	sizes := applyFilterExpression(sizesTestSchema(), sizesTestData(), expression)
+	assert.Len(t, sizes, 1)
+	assert.Equal(t, "s-4vcpu-8gb", sizes[0]["slug"])
+}
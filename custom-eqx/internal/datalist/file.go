@@ -0,0 +1,53 @@
+package datalist
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// valueListFile is the filter value of the in_file and not_in_file match modes: the
+// set of values listed, one per line, in a file. Blank lines are ignored.
+type valueListFile struct {
+	path   string
+	values map[string]struct{}
+}
+
+func (f valueListFile) contains(value string) bool {
+	_, ok := f.values[value]
+	return ok
+}
+
+// Value list files are loaded once and shared by all the filters referencing them.
+var valueListFiles = struct {
+	sync.Mutex
+	files map[string]valueListFile
+}{files: map[string]valueListFile{}}
+
+func loadValueListFile(path string) (valueListFile, error) {
+	valueListFiles.Lock()
+	defer valueListFiles.Unlock()
+	if f, ok := valueListFiles.files[path]; ok {
+		return f, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return valueListFile{}, fmt.Errorf("unable to read values file: %s", err)
+	}
+	defer file.Close()
+	f := valueListFile{path: path, values: map[string]struct{}{}}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value := strings.TrimSpace(scanner.Text()); value != "" {
+			f.values[value] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return valueListFile{}, fmt.Errorf("unable to read values file %s: %s", path, err)
+	}
+	valueListFiles.files[path] = f
+	return f, nil
+}
@@ -0,0 +1,73 @@
+package datalist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_inFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowed.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("CSR1000V\n\n  VSRX \nPA-VM\n"), 0o600))
+
+	recordSchema := map[string]*schema.Schema{
+		"type_code": {Type: schema.TypeString},
+	}
+	records := []map[string]interface{}{
+		{"type_code": "CSR1000V"},
+		{"type_code": "VSRX"},
+		{"type_code": "C8000V"},
+	}
+
+	for matchBy, expected := range map[string][]string{
+		"in_file":     {"CSR1000V", "VSRX"},
+		"not_in_file": {"C8000V"},
+	} {
+		filters, err := expandFilters(recordSchema, []interface{}{
+			map[string]interface{}{
+				"attribute": "type_code",
+				"values":    []interface{}{path},
+				"match_by":  matchBy,
+			},
+		})
+		if err != nil {
+			t.Fatalf("expandFilters returned error: %s", err)
+		}
+		var codes []string
+		for _, record := range applyFilters(recordSchema, records, filters) {
+			codes = append(codes, record["type_code"].(string))
+		}
+		assert.Equal(t, expected, codes, "match_by %s", matchBy)
+	}
+}
+
+func TestLoadValueListFile_cached(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "allowed.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("one\n"), 0o600))
+	_, err := loadValueListFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(path))
+	// when
+	f, err := loadValueListFile(path)
+	// then
+	assert.NoError(t, err, "File is loaded once")
+	assert.True(t, f.contains("one"))
+}
+
+func TestExpandFilters_missingValuesFile(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"type_code": {Type: schema.TypeString},
+	}
+	_, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "type_code",
+			"values":    []interface{}{filepath.Join(t.TempDir(), "missing.txt")},
+			"match_by":  "in_file",
+		},
+	})
+	assert.ErrorContains(t, err, "unable to read values file", "Missing file is rejected before records are loaded")
+}
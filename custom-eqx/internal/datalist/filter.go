@@ -1,24 +1,74 @@
 package datalist
 
 import (
+	"context"
 	"fmt"
 	"regexp"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 var (
-	matchByStringComparison = []string{"in", "re", "substring"}
+	matchByStringComparison = []string{"in", "re", "substring", "metro", "within_last", "age_gt", "age_lt", "before", "after", "enum", "not_in_enum", "id_in", "fuzzy", "bool", "in_file", "not_in_file", "json_path", "str_between"}
 	matchByNumberComparison = []string{"less_than", "less_than_or_equal", "greater_than", "greater_than_or_equal"}
+	// Modes which ignore the filter values
+	matchByValueless = []string{"present"}
+	// String modes which do not take filter values
+	matchByHostname = []string{"resolves", "not_resolves"}
 )
 
+// The mode comparing all of the elements of lists and sets with all of the values,
+// rather than each of the elements with each of the values.
+const matchBySetEquals = "set_equals"
+
+// The match_by modes supported by each of the primitive types and maps. Lists and sets
+// support the modes of their element type.
+var matchByModes = map[schema.ValueType][]string{
+	schema.TypeString: append(append(matchByStringComparison, matchByHostname...), matchByValueless...),
+	schema.TypeBool:   append([]string{"in"}, matchByValueless...),
+	schema.TypeInt:    append(append([]string{"in", "mod"}, matchByNumberComparison...), matchByValueless...),
+	schema.TypeFloat:  append(append([]string{"in", "units"}, matchByNumberComparison...), matchByValueless...),
+	// Map filter values are keys
+	schema.TypeMap: append([]string{"missing_key"}, matchByValueless...),
+}
+
+// Returns all of the supported match_by modes.
+func allMatchByModes() []string {
+	var modes []string
+	seen := map[string]bool{}
+	for _, fieldType := range []schema.ValueType{schema.TypeString, schema.TypeBool, schema.TypeInt, schema.TypeFloat, schema.TypeMap} {
+		for _, mode := range matchByModes[fieldType] {
+			if !seen[mode] {
+				seen[mode] = true
+				modes = append(modes, mode)
+			}
+		}
+	}
+	return append(modes, matchBySetEquals)
+}
+
+func isValuelessMatchBy(matchBy string) bool {
+	for _, mode := range append(matchByValueless, matchByHostname...) {
+		if mode == matchBy {
+			return true
+		}
+	}
+	return false
+}
+
 type commonFilter struct {
 	attribute string
 	values    []interface{}
 	all       bool
 	matchBy   string
+	// Bounds on the number of matching elements of a list or set, which by default
+	// matches when any of its elements matches
+	count *elementCount
+	// Human readable form of the filter, e.g. name in "a" or "b"
+	description string
 }
 
 func filterSchema(allowedAttributes []string) *schema.Schema {
@@ -34,10 +84,58 @@ func filterSchema(allowedAttributes []string) *schema.Schema {
 				},
 				"values": {
 					Type:        schema.TypeList,
-					Description: "The filter values. Filter values are case-sensitive. If you specify multiple values for a filter, the values are joined with an OR by default, and the request returns all results that match any of the specified values",
-					Required:    true,
+					Description: "The filter values. Filter values are case-sensitive. If you specify multiple values for a filter, the values are joined with an OR by default, and the request returns all results that match any of the specified values. Values are ignored by the present mode",
+					Optional:    true,
 					Elem:        &schema.Schema{Type: schema.TypeString},
 				},
+				"allow_empty_values": {
+					Type:        schema.TypeBool,
+					Description: "If is set to true, the filter is skipped when its values are empty, e.g. when they are computed from another resource. Otherwise, empty values are an error",
+					Optional:    true,
+					Default:     false,
+				},
+				"variable": {
+					Type:        schema.TypeString,
+					Description: "The name of a provider-supplied variable, e.g. environment, whose value is used as the filter value instead of values. The variable is resolved when the data source is read",
+					Optional:    true,
+				},
+				"transform": {
+					Type:        schema.TypeList,
+					Description: "Transforms applied in order to the string attribute values before they are compared with the filter values. Each one of: lower, upper, trim, base64decode, hexdecode. Values which cannot be decoded do not match the filter",
+					Optional:    true,
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringInSlice(stringTransformNames(), false),
+					},
+				},
+				"at_least": {
+					Type:        schema.TypeInt,
+					Description: "The minimum number of elements of a list or set attribute matching the values. Ignored when negative",
+					Optional:    true,
+					Default:     -1,
+				},
+				"at_most": {
+					Type:        schema.TypeInt,
+					Description: "The maximum number of elements of a list or set attribute matching the values. Ignored when negative",
+					Optional:    true,
+					Default:     -1,
+				},
+				"ignore_key_case": {
+					Type:        schema.TypeBool,
+					Description: "If is set to true, the keys of map attributes are matched case-insensitively, e.g. env matches Env. Map keys are case-sensitive by default",
+					Optional:    true,
+					Default:     false,
+				},
+				"tolerance": {
+					Type:        schema.TypeFloat,
+					Description: "The absolute tolerance within which float attribute values are considered equal to the filter values, e.g. 0.01 makes 1.49 match 1.5. Defaults to 0.000001",
+					Optional:    true,
+				},
+				"max_distance": {
+					Type:        schema.TypeInt,
+					Description: fmt.Sprintf("The maximum edit distance between the attribute values and the filter values of the fuzzy match mode. Defaults to %d", defaultFuzzyMaxDistance),
+					Optional:    true,
+				},
 				"all": {
 					Type:        schema.TypeBool,
 					Description: "If is set to true, the values are joined with an AND, and the requests returns only the results that match all specified values",
@@ -46,22 +144,22 @@ func filterSchema(allowedAttributes []string) *schema.Schema {
 				},
 				"match_by": {
 					Type:         schema.TypeString,
-					Description:  "The type of comparison to apply. One of: in (default), re, substring, less_than, less_than_or_equal, greater_than, greater_than_or_equal",
+					Description:  matchByDescription(),
 					Optional:     true,
 					Default:      "in",
-					ValidateFunc: validation.StringInSlice(append(matchByNumberComparison, matchByStringComparison...), false),
+					ValidateFunc: validation.StringInSlice(allMatchByModes(), false),
 				},
 			},
 		},
 		Optional:    true,
-		Description: "One or more attribute/values pairs on which to filter results",
+		Description: "One or more attribute/values pairs on which to filter results. Filters are joined with an AND, including filters on the same attribute unless same_attribute_filters is or",
 	}
 }
 
 func expandFilters(recordSchema map[string]*schema.Schema, rawFilters []interface{}) ([]commonFilter, error) {
-	expandedFilters := make([]commonFilter, len(rawFilters))
+	expandedFilters := make([]commonFilter, 0, len(rawFilters))
 
-	for i, rawFilter := range rawFilters {
+	for _, rawFilter := range rawFilters {
 		f := rawFilter.(map[string]interface{})
 
 		attr := f["attribute"].(string)
@@ -74,12 +172,64 @@ func expandFilters(recordSchema map[string]*schema.Schema, rawFilters []interfac
 		if v, ok := f["match_by"].(string); ok {
 			matchBy = v
 		}
+		if err := validateMatchBy(attr, s, matchBy); err != nil {
+			return nil, err
+		}
+
+		var expandedFilterValues []interface{}
+		if !isValuelessMatchBy(matchBy) {
+			rawValues, _ := f["values"].([]interface{})
+			if len(rawValues) == 0 {
+				// Values computed from other resources may be empty, which is usually a
+				// mistake rather than a request for all or no results
+				if allow, _ := f["allow_empty_values"].(bool); allow {
+					continue
+				}
+				return nil, fmt.Errorf("filter on '%s' has no values, set allow_empty_values to skip it when its values are empty", attr)
+			}
+			ev, err := expandFilterValues(rawValues, s, matchBy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter value for field '%s': %s", attr, err)
+			}
+			expandedFilterValues = ev
+		}
+		if matchBy == "resolves" || matchBy == "not_resolves" {
+			expandedFilterValues = []interface{}{newHostnameResolver(context.Background())}
+		}
+
+		if v, ok := f["max_distance"].(int); ok && v != 0 {
+			ev, err := maxDistanceFilterValues(attr, matchBy, expandedFilterValues, v)
+			if err != nil {
+				return nil, err
+			}
+			expandedFilterValues = ev
+		}
 
-		expandedFilterValues, err := expandFilterValues(f["values"].([]interface{}), s, matchBy)
+		var transforms []string
+		if rawTransforms, ok := f["transform"].([]interface{}); ok {
+			for _, t := range rawTransforms {
+				transforms = append(transforms, t.(string))
+			}
+		}
+		expandedFilterValues, err := transformFilterValues(attr, s, expandedFilterValues, transforms)
 		if err != nil {
 			return nil, err
 		}
 
+		if v, ok := f["tolerance"].(float64); ok && v != 0 {
+			expandedFilterValues, err = toleranceFilterValues(attr, s, expandedFilterValues, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if v, ok := f["ignore_key_case"].(bool); ok && v {
+			expandedFilterValues, err = ignoreKeyCaseFilterValues(attr, s, expandedFilterValues)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		for _, nc := range matchByNumberComparison {
 			if matchBy == nc {
 				if len(expandedFilterValues) != 1 {
@@ -93,19 +243,54 @@ func expandFilters(recordSchema map[string]*schema.Schema, rawFilters []interfac
 			all = v.(bool)
 		}
 
+		count, err := expandElementCount(attr, s, matchBy, f)
+		if err != nil {
+			return nil, err
+		}
+
+		if matchBy == matchBySetEquals {
+			expandedFilterValues = []interface{}{multisetFilterValue(expandedFilterValues)}
+		}
+
 		expandedFilter := commonFilter{
-			attribute: attr,
-			values:    expandedFilterValues,
-			all:       all,
-			matchBy:   matchBy,
+			attribute:   attr,
+			values:      expandedFilterValues,
+			all:         all,
+			matchBy:     matchBy,
+			count:       count,
+			description: describeFilter(f),
 		}
 
-		expandedFilters[i] = expandedFilter
+		expandedFilters = append(expandedFilters, expandedFilter)
 	}
 
 	return expandedFilters, nil
 }
 
+// Ensures that the match_by mode can be applied to values of the attribute's type.
+func validateMatchBy(attr string, s *schema.Schema, matchBy string) error {
+	if matchBy == matchBySetEquals {
+		if elem, ok := s.Elem.(*schema.Schema); !ok || (s.Type != schema.TypeList && s.Type != schema.TypeSet) || !isPrimitiveType(elem.Type) {
+			return fmt.Errorf("match_by '%s' is only supported by lists and sets of primitive values, not by field '%s' of type %s", matchBy, attr, s.Type)
+		}
+		return nil
+	}
+	fieldType := s.Type
+	if elem, ok := s.Elem.(*schema.Schema); ok && !isPrimitiveType(fieldType) && fieldType != schema.TypeMap {
+		fieldType = elem.Type
+	}
+	allowed, ok := matchByModes[fieldType]
+	if !ok {
+		return fmt.Errorf("field '%s' of type %s cannot be filtered", attr, fieldType)
+	}
+	for _, mode := range allowed {
+		if mode == matchBy {
+			return nil
+		}
+	}
+	return fmt.Errorf("match_by '%s' is not supported by field '%s' of type %s, allowed values are: %s", matchBy, attr, fieldType, strings.Join(allowed, ", "))
+}
+
 func isPrimitiveType(fieldType schema.ValueType) bool {
 	switch fieldType {
 	case schema.TypeString,
@@ -119,8 +304,8 @@ func isPrimitiveType(fieldType schema.ValueType) bool {
 }
 
 // Expands a single filter value (which is a string) into the Go type that can actually be
-// used for comparisons when filtering. This should not be called with container or
-// composite types.
+// used for comparisons when filtering, failing when it cannot be coerced to that type.
+// This should not be called with container or composite types.
 func expandPrimitiveFilterValue(
 	filterValue string,
 	fieldType schema.ValueType,
@@ -131,7 +316,7 @@ func expandPrimitiveFilterValue(
 	switch fieldType {
 	case schema.TypeString:
 		switch matchBy {
-		case "in", "substring":
+		case "in", "substring", "metro", "str_between":
 			expandedValue = filterValue
 		case "re":
 			re, err := regexp.Compile(filterValue)
@@ -139,30 +324,77 @@ func expandPrimitiveFilterValue(
 				return nil, fmt.Errorf("unable to parse value as regular expression: %s: %s", filterValue, err)
 			}
 			expandedValue = re
+		case "within_last":
+			duration, err := time.ParseDuration(filterValue)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse value as duration: %s: %s", filterValue, err)
+			}
+			expandedValue = timeNow().Add(-duration)
+		case "json_path":
+			v, err := parseJSONPathFilterValue(filterValue)
+			if err != nil {
+				return nil, err
+			}
+			expandedValue = v
+		case "before", "after":
+			t, err := parseTimestampFilterValue(filterValue)
+			if err != nil {
+				return nil, err
+			}
+			expandedValue = t
+		case "age_gt", "age_lt":
+			duration, err := time.ParseDuration(filterValue)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse value as duration: %s: %s", filterValue, err)
+			}
+			expandedValue = duration
+		case "enum":
+			expandedValue = newEnumFilterValue(filterValue, nil)
+		case "not_in_enum", "id_in":
+			expandedValue = filterValue
+		case "fuzzy":
+			expandedValue = fuzzyFilterValue{value: strings.ToLower(filterValue), maxDistance: defaultFuzzyMaxDistance}
+		case "bool":
+			b, err := expandBoolTokenFilterValue(filterValue)
+			if err != nil {
+				return nil, err
+			}
+			expandedValue = b
+		case "in_file", "not_in_file":
+			f, err := loadValueListFile(filterValue)
+			if err != nil {
+				return nil, err
+			}
+			expandedValue = f
 		default:
 			panic("unreachable")
 		}
 
-	case schema.TypeBool:
-		boolValue, err := strconv.ParseBool(filterValue)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse value as bool: %s: %s", filterValue, err)
+	case schema.TypeFloat:
+		if matchBy == "units" {
+			floatValue, err := parseBitRate(filterValue)
+			if err != nil {
+				return nil, err
+			}
+			expandedValue = floatValue
+			break
 		}
-		expandedValue = boolValue
+		fallthrough
 
-	case schema.TypeInt:
-		intValue, err := strconv.Atoi(filterValue)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse value as integer: %s: %s", filterValue, err)
+	case schema.TypeBool, schema.TypeInt:
+		if matchBy == "mod" {
+			mod, err := parseModFilterValue(filterValue)
+			if err != nil {
+				return nil, err
+			}
+			expandedValue = mod
+			break
 		}
-		expandedValue = intValue
-
-	case schema.TypeFloat:
-		floatValue, err := strconv.ParseFloat(filterValue, 64)
+		coerced, err := coerceFilterValue(filterValue, fieldType)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse value as floating point: %s: %s", filterValue, err)
+			return nil, err
 		}
-		expandedValue = floatValue
+		expandedValue = coerced
 
 	default:
 		panic("unreachable")
@@ -180,11 +412,18 @@ func expandFilterValues(
 ) ([]interface{}, error) {
 	expandedFilterValues := make([]interface{}, len(rawFilterValues))
 
+	// The values of set_equals are compared with the elements the same way as by in
+	if matchBy == matchBySetEquals {
+		matchBy = "in"
+	}
+
 	for i, rawFilterValue := range rawFilterValues {
 		filterValue := rawFilterValue.(string)
 		var expandedValue interface{}
 
-		if isPrimitiveType(fieldSchema.Type) {
+		if fieldSchema.Type == schema.TypeMap {
+			expandedValue = filterValue
+		} else if isPrimitiveType(fieldSchema.Type) {
 			ev, err := expandPrimitiveFilterValue(filterValue, fieldSchema.Type, matchBy)
 			if err != nil {
 				return nil, err
@@ -209,37 +448,60 @@ func expandFilterValues(
 		expandedFilterValues[i] = expandedValue
 	}
 
+	// The values of these modes form a single set, which record values are looked up in
+	switch matchBy {
+	case "not_in_enum":
+		return []interface{}{newEnumSetFilterValue(expandedFilterValues)}, nil
+	case "id_in":
+		return []interface{}{newIDSetFilterValue(expandedFilterValues)}, nil
+	case "str_between":
+		v, err := newStrBetweenFilterValue(expandedFilterValues)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{v}, nil
+	}
 	return expandedFilterValues, nil
 }
 
-func applyFilters(recordSchema map[string]*schema.Schema, records []map[string]interface{}, filters []commonFilter) []map[string]interface{} {
-	for _, f := range filters {
-		// Handle multiple filters by applying them in order
-		var filteredRecords []map[string]interface{}
+func filterMatches(recordSchema map[string]*schema.Schema, record map[string]interface{}, f commonFilter) bool {
+	if f.count != nil {
+		return f.count.matches(countMatchingElements(recordSchema[f.attribute], record[f.attribute], f))
+	}
 
-		filterFunc := func(record map[string]interface{}) bool {
-			result := f.all
+	if record[f.attribute] == nil {
+		// Identifier attributes are not present in records flattened without them,
+		// while unset maps are missing any key
+		return f.matchBy == "missing_key"
+	}
 
-			for _, filterValue := range f.values {
-				thisValueMatches := valueMatches(recordSchema[f.attribute], record[f.attribute], filterValue, f.matchBy)
-				if !f.all {
-					result = result || thisValueMatches
-				} else {
-					result = result && thisValueMatches
-				}
-			}
+	if f.matchBy == "present" {
+		return valuePresent(recordSchema[f.attribute], record[f.attribute])
+	}
 
-			return result
-		}
+	if f.matchBy == matchBySetEquals {
+		return f.values[0].(multisetFilterValue).equals(recordSchema[f.attribute], record[f.attribute])
+	}
 
-		for _, record := range records {
-			if filterFunc(record) {
-				filteredRecords = append(filteredRecords, record)
-			}
-		}
+	result := f.all
 
-		records = filteredRecords
+	for _, filterValue := range f.values {
+		thisValueMatches := valueMatches(recordSchema[f.attribute], record[f.attribute], filterValue, f.matchBy)
+		if !f.all {
+			result = result || thisValueMatches
+		} else {
+			result = result && thisValueMatches
+		}
 	}
 
-	return records
+	return result
+}
+
+// Applies the filters to the records. The flat list of filters is a shorthand for an
+// expression joining all of the filters with an AND.
+func applyFilters(recordSchema map[string]*schema.Schema, records []map[string]interface{}, filters []commonFilter) []map[string]interface{} {
+	if len(filters) == 0 {
+		return records
+	}
+	return applyFilterExpression(recordSchema, records, compileFilters(filters))
 }
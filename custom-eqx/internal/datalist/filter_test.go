@@ -3,6 +3,7 @@ package datalist
 import (
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
@@ -158,160 +159,160 @@ func TestApplyFilters(t *testing.T) {
 		{
 			"BySlug",
 			commonFilter{
-				"slug",
-				[]interface{}{"s-1vcpu-1gb", "s-4vcpu-8gb"},
-				false,
-				"in",
+				attribute: "slug",
+				values:    []interface{}{"s-1vcpu-1gb", "s-4vcpu-8gb"},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb"},
 		},
 		{
 			"ByMemory",
 			commonFilter{
-				"memory",
-				[]interface{}{1024, 8192},
-				false,
-				"in",
+				attribute: "memory",
+				values:    []interface{}{1024, 8192},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb", "m-1vcpu-8gb"},
 		},
 		{
 			"ByCPU",
 			commonFilter{
-				"vcpus",
-				[]interface{}{1, 4},
-				false,
-				"in",
+				attribute: "vcpus",
+				values:    []interface{}{1, 4},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb", "m-1vcpu-8gb"},
 		},
 		{
 			"ByDisk",
 			commonFilter{
-				"disk",
-				[]interface{}{25, 160},
-				false,
-				"in",
+				attribute: "disk",
+				values:    []interface{}{25, 160},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb"},
 		},
 		{
 			"ByTransfer",
 			commonFilter{
-				"transfer",
-				[]interface{}{1.0, 5.0},
-				false,
-				"in",
+				attribute: "transfer",
+				values:    []interface{}{1.0, 5.0},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb"},
 		},
 		{
 			"ByPriceMonthly",
 			commonFilter{
-				"price_monthly",
-				[]interface{}{5.0, 40.0},
-				false,
-				"in",
+				attribute: "price_monthly",
+				values:    []interface{}{5.0, 40.0},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb"},
 		},
 		{
 			"ByPriceHourly",
 			commonFilter{
-				"price_hourly",
-				[]interface{}{0.00744, 0.05952},
-				false,
-				"in",
+				attribute: "price_hourly",
+				values:    []interface{}{0.00744, 0.05952},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb", "m-1vcpu-8gb"},
 		},
 		{
 			"ByPriceHourlyGreaterThan",
 			commonFilter{
-				"price_hourly",
-				[]interface{}{0.059519},
-				false,
-				"greater_than",
+				attribute: "price_hourly",
+				values:    []interface{}{0.059519},
+				all:       false,
+				matchBy:   "greater_than",
 			},
 			[]string{"s-4vcpu-8gb", "m-1vcpu-8gb"},
 		},
 		{
 			"ByPriceHourlyLessThanOrEqual",
 			commonFilter{
-				"price_hourly",
-				[]interface{}{0.0223200},
-				false,
-				"less_than_or_equal",
+				attribute: "price_hourly",
+				values:    []interface{}{0.0223200},
+				all:       false,
+				matchBy:   "less_than_or_equal",
 			},
 			[]string{"s-1vcpu-1gb", "s-2vcpu-2gb"},
 		},
 		{
 			"ByRegions",
 			commonFilter{
-				"regions",
-				[]interface{}{"sgp1", "ams2"},
-				false,
-				"in",
+				attribute: "regions",
+				values:    []interface{}{"sgp1", "ams2"},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb"},
 		},
 		{
 			"ByRegionsSet",
 			commonFilter{
-				"regions_set",
-				[]interface{}{"sgp1", "ams2"},
-				false,
-				"in",
+				attribute: "regions_set",
+				values:    []interface{}{"sgp1", "ams2"},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb"},
 		},
 		{
 			"ByAvailable",
 			commonFilter{
-				"available",
-				[]interface{}{true},
-				false,
-				"in",
+				attribute: "available",
+				values:    []interface{}{true},
+				all:       false,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb"},
 		},
 		{
 			"ByRegionsSetWithAllValues",
 			commonFilter{
-				"regions_set",
-				[]interface{}{"nyc1", "ams1"},
-				true,
-				"in",
+				attribute: "regions_set",
+				values:    []interface{}{"nyc1", "ams1"},
+				all:       true,
+				matchBy:   "in",
 			},
 			[]string{"m-1vcpu-8gb"},
 		},
 		{
 			"ByDeploymentTypesAllValues",
 			commonFilter{
-				"deployment_types",
-				[]interface{}{"on_demand", "spot_market"},
-				true,
-				"in",
+				attribute: "deployment_types",
+				values:    []interface{}{"on_demand", "spot_market"},
+				all:       true,
+				matchBy:   "in",
 			},
 			[]string{"s-1vcpu-1gb", "s-4vcpu-8gb"},
 		},
 		{
 			"BySlugWithRegularExpression",
 			commonFilter{
-				"slug",
-				[]interface{}{regexp.MustCompile("8gb$")},
-				false,
-				"re",
+				attribute: "slug",
+				values:    []interface{}{regexp.MustCompile("8gb$")},
+				all:       false,
+				matchBy:   "re",
 			},
 			[]string{"s-4vcpu-8gb", "m-1vcpu-8gb"},
 		},
 		{
 			"ByRegionSetWithSubstring",
 			commonFilter{
-				"regions_set",
-				[]interface{}{"nyc"},
-				false,
-				"substring",
+				attribute: "regions_set",
+				values:    []interface{}{"nyc"},
+				all:       false,
+				matchBy:   "substring",
 			},
 			[]string{"s-2vcpu-2gb", "m-1vcpu-8gb"},
 		},
@@ -328,3 +329,260 @@ func TestApplyFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyFilters_byID(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name": {
+			Type: schema.TypeString,
+		},
+	}
+	records := []map[string]interface{}{
+		{"uuid": "9a4a3a5e-57bb-4a4c-a2b5-b3f2a5d6e7f1", "name": "port-1"},
+		{"uuid": "2c0f5e7a-8c4d-4d9e-b6a1-0d3c2b1a9f8e", "name": "port-2"},
+		{"uuid": "5e1b7d3c-2a9f-4c8e-9d0b-6f4a3e2d1c0b", "name": "port-3"},
+		{"name": "port-4"},
+	}
+	rawFilters := []interface{}{
+		map[string]interface{}{
+			"attribute": "uuid",
+			"values":    []interface{}{"9a4a3a5e-57bb-4a4c-a2b5-b3f2a5d6e7f1", "5E1B7D3C-2A9F-4C8E-9D0B-6F4A3E2D1C0B"},
+		},
+	}
+
+	filterSchema := filterRecordSchema(recordSchema)
+	filters, err := expandFilters(filterSchema, rawFilters)
+	if err != nil {
+		t.Fatalf("expandFilters returned error: %s", err)
+	}
+	filtered := applyFilters(filterSchema, records, filters)
+
+	var names []string
+	for _, record := range filtered {
+		names = append(names, record["name"].(string))
+	}
+	assert.Equal(t, []string{"port-1", "port-3"}, names)
+	assert.Contains(t, computeFilterAttributes(filterSchema), "id")
+	assert.NotContains(t, recordSchema, "uuid", "record schema is not modified")
+}
+
+func TestExpandFilters_invalidMatchBy(t *testing.T) {
+	testCases := []struct {
+		attribute string
+		matchBy   string
+		value     string
+	}{
+		{"slug", "less_than", "foo"},
+		{"slug", "greater_than_or_equal", "foo"},
+		{"available", "greater_than", "true"},
+		{"available", "re", "true"},
+		{"available", "substring", "true"},
+		{"memory", "re", "1024"},
+		{"memory", "substring", "1024"},
+		{"transfer", "re", "1.0"},
+		{"transfer", "substring", "1.0"},
+		{"regions", "less_than", "sgp1"},
+		{"regions_set", "greater_than", "sgp1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.attribute+"_"+testCase.matchBy, func(t *testing.T) {
+			rawFilters := []interface{}{
+				map[string]interface{}{
+					"attribute": testCase.attribute,
+					"values":    []interface{}{testCase.value},
+					"match_by":  testCase.matchBy,
+				},
+			}
+			_, err := expandFilters(sizesTestSchema(), rawFilters)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), testCase.attribute)
+				assert.Contains(t, err.Error(), "allowed values are")
+			}
+		})
+	}
+}
+
+func TestApplyFilters_present(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name": {
+			Type: schema.TypeString,
+		},
+		"ssh_ip_address": {
+			Type: schema.TypeString,
+		},
+		"interfaces": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeString},
+		},
+		"asn": {
+			Type: schema.TypeInt,
+		},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "ssh_ip_address": "10.0.0.1", "interfaces": []interface{}{"eth0"}, "asn": 0},
+		{"name": "dev-2", "ssh_ip_address": "", "interfaces": []interface{}{}, "asn": 65000},
+		{"name": "dev-3", "ssh_ip_address": "10.0.0.3", "interfaces": []interface{}{"eth0", "eth1"}, "asn": 65001},
+	}
+	testCases := []struct {
+		attribute    string
+		expectations []string
+	}{
+		{"ssh_ip_address", []string{"dev-1", "dev-3"}},
+		{"interfaces", []string{"dev-1", "dev-3"}},
+		{"asn", []string{"dev-2", "dev-3"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.attribute, func(t *testing.T) {
+			rawFilters := []interface{}{
+				map[string]interface{}{
+					"attribute": testCase.attribute,
+					"values":    []interface{}{"ignored"},
+					"match_by":  "present",
+				},
+			}
+			filters, err := expandFilters(recordSchema, rawFilters)
+			if err != nil {
+				t.Fatalf("expandFilters returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectations, names)
+		})
+	}
+}
+
+func TestApplyFilters_withinLast(t *testing.T) {
+	now := time.Date(2022, 12, 2, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	recordSchema := map[string]*schema.Schema{
+		"name": {
+			Type: schema.TypeString,
+		},
+		"created_date": {
+			Type: schema.TypeString,
+		},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "created_date": now.Add(-23*time.Hour - 59*time.Minute).Format(time.RFC3339)},
+		{"name": "dev-2", "created_date": now.Add(-24*time.Hour - time.Minute).Format(time.RFC3339)},
+		{"name": "dev-3", "created_date": "2022-12-02T11:30:00+02:00"},
+		{"name": "dev-4", "created_date": ""},
+	}
+
+	filters, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "created_date",
+			"values":    []interface{}{"24h"},
+			"match_by":  "within_last",
+		},
+	})
+	if err != nil {
+		t.Fatalf("expandFilters returned error: %s", err)
+	}
+	var names []string
+	for _, record := range applyFilters(recordSchema, records, filters) {
+		names = append(names, record["name"].(string))
+	}
+	assert.Equal(t, []string{"dev-1", "dev-3"}, names)
+
+	_, err = expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "created_date",
+			"values":    []interface{}{"1 day"},
+			"match_by":  "within_last",
+		},
+	})
+	assert.Error(t, err, "Invalid duration is rejected")
+}
+
+func TestApplyFilters_missingKey(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name": {
+			Type: schema.TypeString,
+		},
+		"tags": {
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{Type: schema.TypeString},
+		},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "tags": map[string]interface{}{"owner": "team-a", "env": "prod"}},
+		{"name": "dev-2", "tags": map[string]interface{}{"env": "dev"}},
+		{"name": "dev-3", "tags": map[string]string{}},
+		{"name": "dev-4"},
+	}
+	testCases := []struct {
+		name         string
+		keys         []interface{}
+		all          bool
+		expectations []string
+	}{
+		{"SingleKey", []interface{}{"owner"}, false, []string{"dev-2", "dev-3", "dev-4"}},
+		{"AnyKey", []interface{}{"owner", "env"}, false, []string{"dev-2", "dev-3", "dev-4"}},
+		{"AllKeys", []interface{}{"owner", "env"}, true, []string{"dev-3", "dev-4"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{
+					"attribute": "tags",
+					"values":    testCase.keys,
+					"all":       testCase.all,
+					"match_by":  "missing_key",
+				},
+			})
+			if err != nil {
+				t.Fatalf("expandFilters returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectations, names)
+		})
+	}
+
+	_, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "tags",
+			"values":    []interface{}{"owner"},
+			"match_by":  "in",
+		},
+	})
+	assert.Error(t, err, "Maps only support missing_key and present modes")
+}
+
+func TestExpandFilters_emptyValues(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"name":   {Type: schema.TypeString},
+		"status": {Type: schema.TypeString},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "status": "PROVISIONED"},
+		{"name": "dev-2", "status": "FAILED"},
+	}
+	statusFilter := map[string]interface{}{"attribute": "status", "values": []interface{}{"PROVISIONED"}}
+	// when
+	_, err := expandFilters(recordSchema, []interface{}{
+		statusFilter,
+		map[string]interface{}{"attribute": "name", "values": []interface{}{}},
+	})
+	filters, errAllowed := expandFilters(recordSchema, []interface{}{
+		statusFilter,
+		map[string]interface{}{"attribute": "name", "values": []interface{}{}, "allow_empty_values": true},
+	})
+	// then
+	if assert.Error(t, err, "Empty values are rejected by default") {
+		assert.Contains(t, err.Error(), "filter on 'name' has no values")
+	}
+	assert.NoError(t, errAllowed)
+	assert.Len(t, filters, 1, "Filters with empty values are skipped when allowed")
+	assert.Equal(t, []map[string]interface{}{records[0]}, applyFilters(recordSchema, records, filters))
+}
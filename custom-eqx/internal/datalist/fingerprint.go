@@ -0,0 +1,85 @@
+package datalist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// FingerprintAttribute is the attribute of the records holding the SHA-256 fingerprint
+// of the attributes listed in `fingerprint_attributes`. It is only added to record
+// schemas which do not define it.
+const FingerprintAttribute = "fingerprint"
+
+// Returns a copy of the record schema including the fingerprint, so that it can be
+// filtered and sorted on like any other attribute.
+func withFingerprintAttribute(recordSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	fingerprinted := make(map[string]*schema.Schema, len(recordSchema)+1)
+	for attr, s := range recordSchema {
+		fingerprinted[attr] = s
+	}
+	fingerprinted[FingerprintAttribute] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The SHA-256 fingerprint of the attributes listed in fingerprint_attributes, in hexadecimal",
+	}
+	return fingerprinted
+}
+
+func fingerprintAttributesSchema(recordAttributes []string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "The attributes whose values are hashed into the fingerprint of each result, which changes whenever any of them changes. The fingerprint can be filtered and sorted on",
+		Optional:    true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringInSlice(recordAttributes, false),
+		},
+	}
+}
+
+// Returns the fingerprint of the attributes of the record: the SHA-256 hash of their
+// values encoded in JSON, keyed by attribute name. Set elements are sorted, so the
+// fingerprint does not depend on their order.
+func fingerprintRecord(record map[string]interface{}, attributes []string) (string, error) {
+	values := make(map[string]interface{}, len(attributes))
+	for _, attr := range attributes {
+		values[attr] = fingerprintValue(record[attr])
+	}
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("unable to fingerprint record: %s", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func fingerprintValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *schema.Set:
+		elements := make([]string, v.Len())
+		for i, element := range v.List() {
+			encoded, _ := json.Marshal(fingerprintValue(element))
+			elements[i] = string(encoded)
+		}
+		sort.Strings(elements)
+		return elements
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, element := range v {
+			list[i] = fingerprintValue(element)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			m[key] = fingerprintValue(element)
+		}
+		return m
+	}
+	return value
+}
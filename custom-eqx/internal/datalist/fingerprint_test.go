@@ -0,0 +1,65 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprintRecord(t *testing.T) {
+	// given
+	attributes := []string{"name", "tags"}
+	record := map[string]interface{}{
+		"name":   "dev-1",
+		"status": "PROVISIONED",
+		"tags":   schema.NewSet(schema.HashString, []interface{}{"a", "b"}),
+	}
+	reordered := map[string]interface{}{
+		"name":   "dev-1",
+		"status": "DEPROVISIONED",
+		"tags":   schema.NewSet(schema.HashString, []interface{}{"b", "a"}),
+	}
+	renamed := map[string]interface{}{
+		"name": "dev-2",
+		"tags": schema.NewSet(schema.HashString, []interface{}{"a", "b"}),
+	}
+	// when
+	fingerprint, err := fingerprintRecord(record, attributes)
+	again, _ := fingerprintRecord(record, attributes)
+	reorderedFingerprint, _ := fingerprintRecord(reordered, attributes)
+	renamedFingerprint, _ := fingerprintRecord(renamed, attributes)
+	// then
+	assert.Nil(t, err)
+	assert.Len(t, fingerprint, 64, "Fingerprint is a hexadecimal SHA-256 hash")
+	assert.Equal(t, fingerprint, again, "Fingerprint is stable")
+	assert.Equal(t, fingerprint, reorderedFingerprint, "Fingerprint ignores set order and other attributes")
+	assert.NotEqual(t, fingerprint, renamedFingerprint, "Fingerprint changes with its attributes")
+}
+
+func TestNewResource_fingerprint(t *testing.T) {
+	// given
+	records := []interface{}{
+		map[string]interface{}{"name": "dev-1", "metro_code": "SV"},
+		map[string]interface{}{"name": "dev-2", "metro_code": "DC"},
+	}
+	expected, _ := fingerprintRecord(records[1].(map[string]interface{}), []string{"metro_code"})
+	resource := newTestResource(map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+	}, records, nil)
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"fingerprint_attributes": []interface{}{"metro_code"},
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": FingerprintAttribute, "values": []interface{}{expected}},
+		},
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	assert.Equal(t, 1, d.Get("devices.#"))
+	assert.Equal(t, "dev-2", d.Get("devices.0.name"))
+	assert.Equal(t, expected, d.Get("devices.0.fingerprint"))
+}
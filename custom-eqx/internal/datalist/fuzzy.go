@@ -0,0 +1,83 @@
+package datalist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The maximum edit distance of the fuzzy match mode, unless a filter sets its own.
+const defaultFuzzyMaxDistance = 2
+
+// fuzzyFilterValue is the filter value of the fuzzy match mode, matching strings
+// within an edit distance of the value.
+type fuzzyFilterValue struct {
+	value       string
+	maxDistance int
+}
+
+func (v fuzzyFilterValue) matches(value string) bool {
+	return levenshteinDistance(strings.ToLower(value), v.value, v.maxDistance) <= v.maxDistance
+}
+
+// Sets the maximum edit distance of the fuzzy filter values, which is not supported by
+// other match modes.
+func maxDistanceFilterValues(attr, matchBy string, values []interface{}, maxDistance int) ([]interface{}, error) {
+	if matchBy != "fuzzy" {
+		return nil, fmt.Errorf("max_distance is not supported by match_by '%s' of field '%s'", matchBy, attr)
+	}
+	if maxDistance < 0 {
+		return nil, fmt.Errorf("max_distance of field '%s' cannot be negative, got: %d", attr, maxDistance)
+	}
+	for i, value := range values {
+		v := value.(fuzzyFilterValue)
+		v.maxDistance = maxDistance
+		values[i] = v
+	}
+	return values, nil
+}
+
+// Returns the Levenshtein distance between the strings: the minimum number of
+// single character insertions, deletions and substitutions changing one into the
+// other. The computation stops early once the distance exceeds the limit, returning
+// limit+1.
+func levenshteinDistance(a, b string, limit int) int {
+	s, t := []rune(a), []rune(b)
+	if len(s) < len(t) {
+		s, t = t, s
+	}
+	if len(s)-len(t) > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+			if current[j] < rowMin {
+				rowMin = current[j]
+			}
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+	return previous[len(t)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
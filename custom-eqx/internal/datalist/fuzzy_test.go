@@ -0,0 +1,82 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"router-1", "router-1", 0},
+		{"router-1", "rotuer-1", 2},
+		{"router-1", "router-12", 1},
+		{"router", "", 6},
+		{"kitten", "sitting", 3},
+		{"münchen", "munchen", 1},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, levenshteinDistance(testCase.a, testCase.b, 10), "distance between %q and %q", testCase.a, testCase.b)
+		assert.Equal(t, testCase.expected, levenshteinDistance(testCase.b, testCase.a, 10), "distance between %q and %q", testCase.b, testCase.a)
+	}
+	assert.Equal(t, 2, levenshteinDistance("router", "", 1), "Distance beyond the limit is reported as limit+1")
+}
+
+func TestApplyFilters_fuzzy(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+	}
+	records := []map[string]interface{}{
+		{"name": "router-1"},
+		{"name": "Rotuer-1"},
+		{"name": "router-10"},
+		{"name": "switch-1"},
+	}
+	testCases := []struct {
+		maxDistance int
+		expected    []string
+	}{
+		{0, []string{"router-1", "Rotuer-1", "router-10"}},
+		{1, []string{"router-1", "router-10"}},
+		{3, []string{"router-1", "Rotuer-1", "router-10"}},
+	}
+	for _, testCase := range testCases {
+		filters, err := expandFilters(recordSchema, []interface{}{
+			map[string]interface{}{
+				"attribute":    "name",
+				"values":       []interface{}{"ROUTER-1"},
+				"match_by":     "fuzzy",
+				"max_distance": testCase.maxDistance,
+			},
+		})
+		// when
+		var names []string
+		for _, record := range applyFilters(recordSchema, records, filters) {
+			names = append(names, record["name"].(string))
+		}
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expected, names, "Names within max_distance %d match", testCase.maxDistance)
+	}
+}
+
+func TestExpandFilters_maxDistanceUnsupported(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+	}
+	for _, rawFilter := range []map[string]interface{}{
+		{"attribute": "name", "values": []interface{}{"router-1"}, "match_by": "in", "max_distance": 1},
+		{"attribute": "name", "values": []interface{}{"router-1"}, "match_by": "fuzzy", "max_distance": -1},
+	} {
+		// when
+		_, err := expandFilters(recordSchema, []interface{}{rawFilter})
+		// then
+		assert.Error(t, err, "max_distance is rejected for %v", rawFilter)
+	}
+}
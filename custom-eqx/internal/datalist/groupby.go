@@ -0,0 +1,21 @@
+package datalist
+
+import "fmt"
+
+// Returns the number of records sharing each value of the attribute. Records without
+// a value for the attribute are not counted.
+func countRecordsByGroup(records []map[string]interface{}, attribute string) map[string]interface{} {
+	counts := map[string]int{}
+	for _, record := range records {
+		value, ok := record[attribute]
+		if !ok || value == nil {
+			continue
+		}
+		counts[fmt.Sprint(value)]++
+	}
+	groups := make(map[string]interface{}, len(counts))
+	for key, count := range counts {
+		groups[key] = count
+	}
+	return groups
+}
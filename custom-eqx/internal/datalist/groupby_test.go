@@ -0,0 +1,53 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResource_groupBy(t *testing.T) {
+	// given
+	resource := newTestResource(map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+		"status":     {Type: schema.TypeString},
+	}, []interface{}{
+		map[string]interface{}{"name": "dev-1", "metro_code": "SV", "status": "PROVISIONED"},
+		map[string]interface{}{"name": "dev-2", "metro_code": "DC", "status": "PROVISIONED"},
+		map[string]interface{}{"name": "dev-3", "metro_code": "SV", "status": "PROVISIONED"},
+		map[string]interface{}{"name": "dev-4", "metro_code": "AM", "status": "DEPROVISIONED"},
+		map[string]interface{}{"name": "dev-5", "metro_code": "SV", "status": "PROVISIONED"},
+		map[string]interface{}{"name": "dev-6", "status": "PROVISIONED"},
+	}, nil)
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "status", "values": []interface{}{"PROVISIONED"}},
+		},
+		"group_by": "metro_code",
+		"limit":    1,
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	assert.Equal(t, map[string]interface{}{"SV": 3, "DC": 1}, d.Get("counts_by_group"), "Matching results are counted, ignoring the limit")
+	assert.Equal(t, 1, d.Get("devices.#"))
+}
+
+func TestCountRecordsByGroup(t *testing.T) {
+	// given
+	records := []map[string]interface{}{
+		{"cores": 2},
+		{"cores": 4},
+		{"cores": 2},
+		{"cores": nil},
+	}
+	// when
+	groups := countRecordsByGroup(records, "cores")
+	// then
+	assert.Equal(t, map[string]interface{}{"2": 2, "4": 1}, groups)
+	assert.Empty(t, countRecordsByGroup(nil, "cores"))
+}
@@ -0,0 +1,21 @@
+package datalist
+
+// idSetFilterValue is the filter value of the id_in match mode: the set of identifiers
+// listed as values, typically the output of another resource or data source. Empty
+// identifiers, e.g. of resources not created yet, are ignored.
+type idSetFilterValue map[string]struct{}
+
+func newIDSetFilterValue(values []interface{}) idSetFilterValue {
+	set := make(idSetFilterValue, len(values))
+	for _, value := range values {
+		if id := value.(string); id != "" {
+			set[id] = struct{}{}
+		}
+	}
+	return set
+}
+
+func (s idSetFilterValue) contains(id string) bool {
+	_, ok := s[id]
+	return ok
+}
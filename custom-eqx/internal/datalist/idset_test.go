@@ -0,0 +1,48 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_idIn(t *testing.T) {
+	// given
+	recordSchema := filterRecordSchema(map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+	})
+	records := []map[string]interface{}{
+		{"uuid": "port-1", "name": "port-a"},
+		{"uuid": "port-2", "name": "port-b"},
+		{"uuid": "PORT-3", "name": "port-c"},
+		{"name": "port-d"},
+	}
+	testCases := []struct {
+		name     string
+		ids      []interface{}
+		expected []string
+	}{
+		{"Overlapping", []interface{}{"port-2", "port-3", "port-9", ""}, []string{"port-b"}},
+		{"Disjoint", []interface{}{"port-7", "port-8"}, nil},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{
+					"attribute": "uuid",
+					"values":    testCase.ids,
+					"match_by":  "id_in",
+				},
+			})
+			// when
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			// then
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expected, names, "Only records with listed identifiers match, case-sensitively")
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Package jmespath provides a datalist.ExpressionLanguage evaluating JMESPath
+// expressions against the results of data list data sources. It is kept apart from
+// the datalist package, so that data sources only depend on the JMESPath library
+// when they opt in to expressions.
+package jmespath
+
+import (
+	"github.com/artraf/equinix-custom-ne/custom-eqx/internal/datalist"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jmespath/go-jmespath"
+)
+
+// Language compiles JMESPath expressions. Records match when the expression result
+// is truthy in JMESPath terms: neither false, null, nor an empty string, list or
+// object. Records failing the evaluation, e.g. comparing a string with a number, do
+// not match.
+type Language struct{}
+
+func (Language) Compile(expression string) (datalist.RecordPredicate, error) {
+	compiled, err := jmespath.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	return func(record map[string]interface{}) bool {
+		result, err := compiled.Search(normalize(record))
+		return err == nil && truthy(result)
+	}, nil
+}
+
+// Converts the flattened record to the JSON types the JMESPath library operates on:
+// integers become float64, sets become lists and maps of strings become objects.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			normalized[key] = normalize(elem)
+		}
+		return normalized
+	case map[string]string:
+		normalized := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			normalized[key] = elem
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, elem := range v {
+			normalized[i] = normalize(elem)
+		}
+		return normalized
+	case []map[string]interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, elem := range v {
+			normalized[i] = normalize(elem)
+		}
+		return normalized
+	case *schema.Set:
+		return normalize(v.List())
+	case int:
+		return float64(v)
+	}
+	return value
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package jmespath
+
+import (
+	"context"
+	"testing"
+
+	"github.com/artraf/equinix-custom-ne/custom-eqx/internal/datalist"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ datalist.ExpressionLanguage = Language{}
+
+func TestLanguage(t *testing.T) {
+	record := map[string]interface{}{
+		"name":  "dev-1",
+		"cores": 4,
+		"tags":  map[string]string{"env": "prod"},
+		"interfaces": []interface{}{
+			map[string]interface{}{"name": "eth0", "status": "up"},
+			map[string]interface{}{"name": "eth1", "status": "down"},
+		},
+		"zones": schema.NewSet(schema.HashString, []interface{}{"a"}),
+	}
+	testCases := map[string]bool{
+		"name == 'dev-1'":                          true,
+		"cores > `2` && tags.env == 'prod'":        true,
+		"cores >= `8`":                             false,
+		"tags.owner":                               false,
+		"interfaces[?status == 'down'].name | [0]": true,
+		"interfaces[?status == 'deleted']":         false,
+		"contains(zones, 'a')":                     true,
+		"length(interfaces) == `2`":                true,
+		"abs(name)":                                false,
+	}
+	for expression, expected := range testCases {
+		t.Run(expression, func(t *testing.T) {
+			// given
+			predicate, err := Language{}.Compile(expression)
+			assert.NoError(t, err)
+			// when
+			matches := predicate(record)
+			// then
+			assert.Equal(t, expected, matches)
+		})
+	}
+}
+
+func TestLanguage_invalidExpression(t *testing.T) {
+	// when
+	_, err := Language{}.Compile("name ==")
+	// then
+	assert.Error(t, err)
+}
+
+func TestNewResource_expression(t *testing.T) {
+	// given
+	resource := datalist.NewResource(&datalist.ResourceConfig{
+		RecordSchema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString},
+			"tags": {Type: schema.TypeMap, Elem: &schema.Schema{Type: schema.TypeString}},
+		},
+		ResultAttributeName: "devices",
+		FlattenRecord: func(record, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+			return record.(map[string]interface{}), nil
+		},
+		GetRecords: func(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"name": "dev-1", "tags": map[string]interface{}{"env": "prod"}},
+				map[string]interface{}{"name": "dev-2", "tags": map[string]interface{}{"env": "dev"}},
+				map[string]interface{}{"name": "edge-1", "tags": map[string]interface{}{"env": "prod"}},
+			}, nil
+		},
+		ExpressionLanguage: Language{},
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "name", "values": []interface{}{"dev"}, "match_by": "substring"},
+		},
+		"expression": "tags.env == 'prod'",
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	assert.Equal(t, 1, d.Get("devices.#"))
+	assert.Equal(t, "dev-1", d.Get("devices.0.name"))
+	_, ok := resource.Schema["expression"].ValidateFunc("tags.env ==", "expression")
+	assert.NotEmpty(t, ok, "Invalid expressions are rejected")
+}
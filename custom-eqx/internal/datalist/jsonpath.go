@@ -0,0 +1,86 @@
+package datalist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathFilterValue is the filter value of the json_path match mode, matching
+// strings holding a JSON document whose value at the path equals the expected value.
+type jsonPathFilterValue struct {
+	path     []string
+	expected string
+}
+
+// Parses a filter value of the json_path match mode, given as path=value, where the
+// path is a dot separated list of object keys and array indexes, e.g.
+// config.interfaces.0.type=WAN. The path may start with $.
+func parseJSONPathFilterValue(filterValue string) (jsonPathFilterValue, error) {
+	parts := strings.SplitN(filterValue, "=", 2)
+	if len(parts) != 2 {
+		return jsonPathFilterValue{}, fmt.Errorf("unable to parse value as path=value: %s", filterValue)
+	}
+	path := strings.TrimPrefix(strings.TrimSpace(parts[0]), "$.")
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return jsonPathFilterValue{}, fmt.Errorf("path cannot have empty segments: %s", filterValue)
+		}
+	}
+	return jsonPathFilterValue{path: segments, expected: parts[1]}, nil
+}
+
+// Reports whether the value at the path of the JSON document equals the expected
+// value. Strings are compared with their content, and other values with their JSON
+// encoding, e.g. 42, true or null. Documents which cannot be parsed or lack the path
+// do not match.
+func (v jsonPathFilterValue) matches(document string) bool {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	var current interface{}
+	if err := decoder.Decode(&current); err != nil {
+		return false
+	}
+	for _, segment := range v.path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			child, ok := node[segment]
+			if !ok {
+				return false
+			}
+			current = child
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return false
+			}
+			current = node[index]
+		default:
+			return false
+		}
+	}
+	if s, ok := current.(string); ok {
+		return s == v.expected
+	}
+	return jsonEncodingEquals(current, v.expected)
+}
+
+// Reports whether the JSON encoding of the value equals the JSON document, ignoring
+// whitespace and the order of object keys.
+func jsonEncodingEquals(value interface{}, document string) bool {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	var expected interface{}
+	if err := decoder.Decode(&expected); err != nil {
+		return false
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	expectedEncoded, err := json.Marshal(expected)
+	return err == nil && bytes.Equal(encoded, expectedEncoded)
+}
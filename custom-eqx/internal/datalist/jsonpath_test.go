@@ -0,0 +1,61 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_jsonPath(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"name":   {Type: schema.TypeString},
+		"config": {Type: schema.TypeString},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "config": `{"env": "prod", "interfaces": [{"type": "WAN", "speed": 1000}], "ha": true}`},
+		{"name": "dev-2", "config": `{"env": "test", "interfaces": [{"type": "LAN", "speed": 100}, {"type": "WAN"}], "ha": false}`},
+		{"name": "dev-3", "config": `{"env": "prod"`},
+		{"name": "dev-4", "config": ``},
+	}
+	for value, expected := range map[string][]string{
+		"env=prod":                         {"dev-1"},
+		"$.env=test":                       {"dev-2"},
+		"interfaces.0.type=WAN":            {"dev-1"},
+		"interfaces.1.type=WAN":            {"dev-2"},
+		"interfaces.0.speed=1000":          {"dev-1"},
+		"interfaces.0.speed=1e3":           nil,
+		"ha=true":                          {"dev-1"},
+		"interfaces.1={\"type\": \"WAN\"}": {"dev-2"},
+		"interfaces.2.type=WAN":            nil,
+		"env.name=prod":                    nil,
+		"missing=prod":                     nil,
+	} {
+		filters, err := expandFilters(recordSchema, []interface{}{
+			map[string]interface{}{"attribute": "config", "values": []interface{}{value}, "match_by": "json_path"},
+		})
+		// when
+		var names []string
+		for _, record := range applyFilters(recordSchema, records, filters) {
+			names = append(names, record["name"].(string))
+		}
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, expected, names, "Records matching %q", value)
+	}
+}
+
+func TestExpandFilters_jsonPathInvalid(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"config": {Type: schema.TypeString},
+	}
+	for _, value := range []string{"env", "env..name=prod", "=prod"} {
+		// when
+		_, err := expandFilters(recordSchema, []interface{}{
+			map[string]interface{}{"attribute": "config", "values": []interface{}{value}, "match_by": "json_path"},
+		})
+		// then
+		assert.Error(t, err, "Value %q is invalid", value)
+	}
+}
@@ -0,0 +1,21 @@
+package datalist
+
+import "fmt"
+
+// Returns the indexes of the records in the list, keyed by the values of the attribute.
+// Every record must have a distinct, non-empty value.
+func indexRecordsByKey(records []map[string]interface{}, attribute string) (map[string]interface{}, error) {
+	indexes := make(map[string]interface{}, len(records))
+	for i, record := range records {
+		value, ok := record[attribute]
+		key := fmt.Sprint(value)
+		if !ok || value == nil || key == "" {
+			return nil, fmt.Errorf("unable to key results by '%s': record %d has no value", attribute, i)
+		}
+		if j, ok := indexes[key]; ok {
+			return nil, fmt.Errorf("unable to key results by '%s': records %d and %d share the value %q", attribute, j, i, key)
+		}
+		indexes[key] = i
+	}
+	return indexes, nil
+}
@@ -0,0 +1,50 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKeyByResource(records []interface{}) *schema.Resource {
+	return newTestResource(map[string]*schema.Schema{
+		"uuid":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+	}, records, nil)
+}
+
+func TestNewResource_keyBy(t *testing.T) {
+	// given
+	resource := testKeyByResource([]interface{}{
+		map[string]interface{}{"uuid": "dev-1", "metro_code": "SV"},
+		map[string]interface{}{"uuid": "dev-2", "metro_code": "DC"},
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"key_by": "uuid",
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	assert.Equal(t, map[string]interface{}{"dev-1": 0, "dev-2": 1}, d.Get("indexes_by_key"))
+	assert.Equal(t, "DC", d.Get("devices.1.metro_code"), "Indexes refer to the results")
+}
+
+func TestNewResource_keyByDuplicate(t *testing.T) {
+	// given
+	resource := testKeyByResource([]interface{}{
+		map[string]interface{}{"uuid": "dev-1", "metro_code": "SV"},
+		map[string]interface{}{"uuid": "dev-2", "metro_code": "DC"},
+		map[string]interface{}{"uuid": "dev-3", "metro_code": "SV"},
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"key_by": "metro_code",
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.True(t, diags.HasError(), "read returns an error")
+	assert.Equal(t, `unable to key results by 'metro_code': records 0 and 2 share the value "SV"`, diags[0].Summary)
+}
@@ -0,0 +1,30 @@
+package datalist
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// RecordPredicate reports whether a flattened record matches.
+type RecordPredicate func(record map[string]interface{}) bool
+
+// ExpressionLanguage compiles expressions selecting records, e.g. JMESPath
+// expressions, into predicates over the flattened records.
+type ExpressionLanguage interface {
+	Compile(expression string) (RecordPredicate, error)
+}
+
+func expressionSchema(language ExpressionLanguage) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "An expression evaluated against each result, which is kept when the expression is truthy. The expression is joined with an AND with any `filter` blocks and `filter_expression`",
+		Optional:    true,
+		ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+			if _, err := language.Compile(v.(string)); err != nil {
+				errors = append(errors, fmt.Errorf("%q contains an invalid expression: %s", k, err))
+			}
+			return
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResource_limit(t *testing.T) {
+	testCases := []struct {
+		name            string
+		raw             map[string]interface{}
+		expectedFetches int
+		expected        []int
+	}{
+		{
+			"Unsorted",
+			map[string]interface{}{"limit": 3},
+			1,
+			[]int{0, 1, 2},
+		},
+		{
+			"UnsortedFiltered",
+			map[string]interface{}{
+				"limit": 2,
+				"filter": []interface{}{
+					map[string]interface{}{"attribute": "number", "values": []interface{}{"15"}, "match_by": "greater_than_or_equal"},
+				},
+			},
+			2,
+			[]int{15, 16},
+		},
+		{
+			"Sorted",
+			map[string]interface{}{
+				"limit": 3,
+				"sort": []interface{}{
+					map[string]interface{}{"attribute": "number", "direction": "desc"},
+				},
+			},
+			5,
+			[]int{49, 48, 47},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			pager := newTestPager(50, true)
+			resource := newTestNumbersResource(10, pager.getRecordsPage, nil)
+			d := schema.TestResourceDataRaw(t, resource.Schema, testCase.raw)
+			// when
+			diags := resource.ReadContext(context.Background(), d, nil)
+			// then
+			assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+			assert.Equal(t, testCase.expectedFetches, pager.fetches)
+			var numbers []int
+			for _, number := range d.Get("numbers").([]interface{}) {
+				numbers = append(numbers, number.(map[string]interface{})["number"].(int))
+			}
+			assert.Equal(t, testCase.expected, numbers)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package datalist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// caseInsensitiveKey is the filter value of a map filter which matches the keys of the
+// record maps case-insensitively.
+type caseInsensitiveKey string
+
+// Wraps the expanded filter values of a map filter so that keys differing only by case
+// are considered equal, which is only supported by map attributes.
+func ignoreKeyCaseFilterValues(attr string, s *schema.Schema, values []interface{}) ([]interface{}, error) {
+	if s.Type != schema.TypeMap {
+		return nil, fmt.Errorf("ignore_key_case is not supported by field '%s' of type %s", attr, s.Type)
+	}
+	wrapped := make([]interface{}, len(values))
+	for i, value := range values {
+		wrapped[i] = caseInsensitiveKey(value.(string))
+	}
+	return wrapped, nil
+}
+
+// Reports whether the map, as flattened by FlattenRecord, contains a key equal to the
+// key under Unicode case-folding.
+func mapHasKeyFold(value interface{}, key string) bool {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		for k := range m {
+			if strings.EqualFold(k, key) {
+				return true
+			}
+		}
+	case map[string]string:
+		for k := range m {
+			if strings.EqualFold(k, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
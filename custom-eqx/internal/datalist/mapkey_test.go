@@ -0,0 +1,69 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_ignoreKeyCase(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+		"tags": {Type: schema.TypeMap, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	records := []map[string]interface{}{
+		{"name": "dev-1", "tags": map[string]interface{}{"Env": "prod"}},
+		{"name": "dev-2", "tags": map[string]string{"env": "dev"}},
+		{"name": "dev-3", "tags": map[string]interface{}{"ENV": "dev", "owner": "team-a"}},
+		{"name": "dev-4", "tags": map[string]interface{}{"owner": "team-b"}},
+	}
+	testCases := []struct {
+		name          string
+		ignoreKeyCase bool
+		expectations  []string
+	}{
+		{"CaseSensitive", false, []string{"dev-1", "dev-3", "dev-4"}},
+		{"IgnoreKeyCase", true, []string{"dev-4"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{
+					"attribute":       "tags",
+					"values":          []interface{}{"env"},
+					"match_by":        "missing_key",
+					"ignore_key_case": testCase.ignoreKeyCase,
+				},
+			})
+			assert.NoError(t, err)
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectations, names)
+		})
+	}
+
+	_, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute":       "name",
+			"values":          []interface{}{"dev-1"},
+			"ignore_key_case": true,
+		},
+	})
+	assert.EqualError(t, err, "ignore_key_case is not supported by field 'name' of type TypeString")
+}
+
+func TestFilterExpression_ignoreKeyCase(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"tags": {Type: schema.TypeMap, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+
+	expression, err := expandFilterExpression(recordSchema, `{"attribute": "tags", "values": ["env"], "match_by": "missing_key", "ignore_key_case": true}`)
+
+	assert.NoError(t, err)
+	assert.False(t, expression.matches(recordSchema, map[string]interface{}{"tags": map[string]interface{}{"Env": "prod"}}), "Keys differing by case are present")
+	assert.True(t, expression.matches(recordSchema, map[string]interface{}{"tags": map[string]interface{}{"owner": "team-a"}}), "Missing keys match")
+}
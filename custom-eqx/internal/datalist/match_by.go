@@ -0,0 +1,63 @@
+package datalist
+
+import (
+	"strings"
+)
+
+// matchByDocs documents the match_by modes, in the order they are listed in the
+// description of the attribute. Modes documented together share a description, and
+// the modes whose name says it all have none.
+var matchByDocs = []struct {
+	modes       []string
+	description string
+}{
+	{[]string{"in"}, ""},
+	{[]string{"re"}, ""},
+	{[]string{"substring"}, ""},
+	{[]string{"less_than", "less_than_or_equal", "greater_than", "greater_than_or_equal"}, ""},
+	{[]string{"present"}, "matches non-empty strings, lists, sets and maps, and non-zero numbers"},
+	{[]string{"metro"}, "compares metro codes or names, e.g. Ashburn matches DC"},
+	{[]string{"units"}, "compares float values with values carrying a bit rate unit, e.g. 10Gbps, 100Mbps or 1Gibps"},
+	{[]string{"within_last"}, "matches RFC3339 timestamps within a duration before now, e.g. 24h"},
+	{[]string{"age_gt", "age_lt"}, "match RFC3339 timestamps whose age, the time elapsed since them, is greater or less than a duration, e.g. 2160h for 90 days"},
+	{[]string{"before", "after"}, "match RFC3339 timestamps earlier or later than an RFC3339 timestamp; timestamps with different offsets are compared by the instant they refer to, e.g. 2024-01-01T00:00:00-05:00 is after 2024-01-01T04:00:00Z"},
+	{[]string{"enum"}, "compares values case-insensitively after resolving aliases, e.g. active matches PROVISIONED for status attributes"},
+	{[]string{"not_in_enum"}, "matches strings that are none of the values, compared case-insensitively, e.g. to find statuses outside of a known set"},
+	{[]string{"id_in"}, "matches identifiers, such as id or uuid, listed in the values exactly, e.g. to only keep the records whose identifiers are output by another resource"},
+	{[]string{"fuzzy"}, "matches strings within max_distance single character insertions, deletions or substitutions of the values, compared case-insensitively, e.g. rotuer-1 matches router-1"},
+	{[]string{"bool"}, "compares strings holding booleans, e.g. yes, off or 1, with a boolean value; other strings do not match"},
+	{[]string{"resolves", "not_resolves"}, "match hostnames which currently resolve, or not, in DNS, and take no values; each hostname is looked up once per read"},
+	{[]string{"mod"}, "matches integers whose remainder of the division by a divisor is the expected remainder, with values given as divisor:remainder, e.g. 2:0 matches even numbers"},
+	{[]string{"missing_key"}, "matches maps missing any of the keys given as values, or all of them when all is true"},
+	{[]string{"in_file", "not_in_file"}, "match strings listed, or not listed, in the files given as values, which list one value per line"},
+	{[]string{"json_path"}, "matches strings holding a JSON document whose value at a path equals a value, with values given as path=value, where the path is a dot separated list of object keys and array indexes, e.g. config.interfaces.0.type=WAN; strings are compared with their content and other values with their JSON encoding, e.g. enabled=true, while invalid documents and missing paths do not match"},
+	{[]string{"str_between"}, "matches strings between two values, the low and high bounds included, compared byte by byte, e.g. m and n matches m, mx and n but not nb; set transform to lower or upper to compare strings case-insensitively, with bounds of the same case"},
+	{[]string{matchBySetEquals}, "matches lists and sets whose elements are exactly the values, in any order, compared the same way as by the in mode; values repeated in the filter must be repeated as many times in lists, and never match sets, whose elements are unique"},
+}
+
+// Returns the description of the match_by attribute, listing the modes and then
+// describing them.
+func matchByDescription() string {
+	var modes, descriptions []string
+	for _, doc := range matchByDocs {
+		modes = append(modes, doc.modes...)
+		if doc.description == "" {
+			continue
+		}
+		if len(doc.modes) == 1 {
+			descriptions = append(descriptions, "The "+doc.modes[0]+" mode "+doc.description)
+		} else {
+			descriptions = append(descriptions, "The "+joinWords(doc.modes, "and")+" modes "+doc.description)
+		}
+	}
+	modes[0] += " (default)"
+	return "The type of comparison to apply. One of: " + strings.Join(modes, ", ") + ". " + strings.Join(descriptions, ". ")
+}
+
+// Joins the words with commas, and the conjunction before the last one.
+func joinWords(words []string, conjunction string) string {
+	if len(words) == 1 {
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " " + conjunction + " " + words[len(words)-1]
+}
@@ -0,0 +1,27 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchByDocs(t *testing.T) {
+	// given
+	var documented []string
+	for _, doc := range matchByDocs {
+		documented = append(documented, doc.modes...)
+	}
+	// then
+	assert.ElementsMatch(t, allMatchByModes(), documented, "Every mode is documented once")
+}
+
+func TestMatchByDescription(t *testing.T) {
+	// when
+	description := matchByDescription()
+	// then
+	assert.Contains(t, description, "One of: in (default), re, substring, less_than, ")
+	assert.Contains(t, description, ", json_path, str_between, set_equals. ")
+	assert.Contains(t, description, ". The age_gt and age_lt modes match RFC3339 timestamps")
+	assert.Contains(t, description, ". The set_equals mode matches lists and sets")
+}
@@ -0,0 +1,54 @@
+package datalist
+
+import (
+	"strings"
+)
+
+// Metro names, in lower case, which can be used in place of metro codes.
+var metroAliases = map[string]string{
+	"amsterdam":      "AM",
+	"ashburn":        "DC",
+	"atlanta":        "AT",
+	"chicago":        "CH",
+	"dallas":         "DA",
+	"frankfurt":      "FR",
+	"hong kong":      "HK",
+	"london":         "LD",
+	"los angeles":    "LA",
+	"miami":          "MI",
+	"new york":       "NY",
+	"paris":          "PA",
+	"seattle":        "SE",
+	"silicon valley": "SV",
+	"singapore":      "SG",
+	"sydney":         "SY",
+	"tokyo":          "TY",
+	"toronto":        "TR",
+	"washington":     "DC",
+	"washington dc":  "DC",
+}
+
+// NormalizeMetroCode returns the upper case metro code for the given metro code
+// or metro name.
+func NormalizeMetroCode(metro string) string {
+	metro = strings.TrimSpace(metro)
+	if code, ok := metroAliases[strings.ToLower(metro)]; ok {
+		return code
+	}
+	return strings.ToUpper(metro)
+}
+
+// MetroMatches reports whether both metro codes or names refer to the same metro.
+func MetroMatches(metro1, metro2 string) bool {
+	return NormalizeMetroCode(metro1) == NormalizeMetroCode(metro2)
+}
+
+// MetroIn reports whether the metro is one of the given metros.
+func MetroIn(metro string, metros []string) bool {
+	for _, m := range metros {
+		if MetroMatches(metro, m) {
+			return true
+		}
+	}
+	return false
+}
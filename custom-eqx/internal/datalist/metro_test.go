@@ -0,0 +1,60 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeMetroCode(t *testing.T) {
+	testCases := map[string]string{
+		"DC":             "DC",
+		"dc":             "DC",
+		" Dc ":           "DC",
+		"Ashburn":        "DC",
+		"ASHBURN":        "DC",
+		"Silicon Valley": "SV",
+		"XY":             "XY",
+	}
+
+	for metro, expected := range testCases {
+		assert.Equal(t, expected, NormalizeMetroCode(metro), "metro %q", metro)
+	}
+}
+
+func TestApplyFilters_metro(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name": {
+			Type: schema.TypeString,
+		},
+		"metro_code": {
+			Type: schema.TypeString,
+		},
+	}
+	records := []map[string]interface{}{
+		{"name": "port-1", "metro_code": "DC"},
+		{"name": "port-2", "metro_code": "SV"},
+		{"name": "port-3", "metro_code": "dc"},
+	}
+
+	for _, metro := range []string{"Ashburn", "DC", "dc"} {
+		t.Run(metro, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{
+					"attribute": "metro_code",
+					"values":    []interface{}{metro},
+					"match_by":  "metro",
+				},
+			})
+			if err != nil {
+				t.Fatalf("expandFilters returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, []string{"port-1", "port-3"}, names)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package datalist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// modFilterValue is the filter value of the mod match mode, matching integers whose
+// remainder of the division by the divisor is the remainder.
+type modFilterValue struct {
+	divisor   int
+	remainder int
+}
+
+func (v modFilterValue) matches(value int) bool {
+	return value%v.divisor == v.remainder
+}
+
+// Parses a filter value of the mod match mode, given as divisor:remainder.
+func parseModFilterValue(filterValue string) (modFilterValue, error) {
+	parts := strings.Split(filterValue, ":")
+	if len(parts) != 2 {
+		return modFilterValue{}, fmt.Errorf("unable to parse value as divisor:remainder: %s", filterValue)
+	}
+	divisor, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return modFilterValue{}, fmt.Errorf("unable to parse divisor as integer: %s", filterValue)
+	}
+	remainder, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return modFilterValue{}, fmt.Errorf("unable to parse remainder as integer: %s", filterValue)
+	}
+	if divisor == 0 {
+		return modFilterValue{}, fmt.Errorf("divisor cannot be zero: %s", filterValue)
+	}
+	return modFilterValue{divisor: divisor, remainder: remainder}, nil
+}
@@ -0,0 +1,58 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_mod(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"vlan_id": {Type: schema.TypeInt},
+	}
+	var records []map[string]interface{}
+	for vlan := 100; vlan < 106; vlan++ {
+		records = append(records, map[string]interface{}{"vlan_id": vlan})
+	}
+	for value, expected := range map[string][]int{
+		"2:0":     {100, 102, 104},
+		"2:1":     {101, 103, 105},
+		" 3 : 2 ": {101, 104},
+		"-3:1":    {100, 103},
+		"4:5":     nil,
+	} {
+		filters, err := expandFilters(recordSchema, []interface{}{
+			map[string]interface{}{"attribute": "vlan_id", "values": []interface{}{value}, "match_by": "mod"},
+		})
+		// when
+		var vlans []int
+		for _, record := range applyFilters(recordSchema, records, filters) {
+			vlans = append(vlans, record["vlan_id"].(int))
+		}
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, expected, vlans, "VLANs matching %q", value)
+	}
+}
+
+func TestExpandFilters_modInvalid(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"vlan_id": {Type: schema.TypeInt},
+		"name":    {Type: schema.TypeString},
+	}
+	for _, rawFilter := range []map[string]interface{}{
+		{"attribute": "vlan_id", "values": []interface{}{"0:0"}, "match_by": "mod"},
+		{"attribute": "vlan_id", "values": []interface{}{"2"}, "match_by": "mod"},
+		{"attribute": "vlan_id", "values": []interface{}{"two:0"}, "match_by": "mod"},
+		{"attribute": "vlan_id", "values": []interface{}{"2:even"}, "match_by": "mod"},
+		{"attribute": "name", "values": []interface{}{"2:0"}, "match_by": "mod"},
+	} {
+		// when
+		_, err := expandFilters(recordSchema, []interface{}{rawFilter})
+		// then
+		assert.Error(t, err, "Filter %v is rejected", rawFilter)
+	}
+}
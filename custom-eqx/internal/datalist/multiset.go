@@ -0,0 +1,42 @@
+package datalist
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// multisetFilterValue is the filter value of the set_equals match mode: the values
+// which the elements of a list or set must equal, in any order. Values repeated in
+// the filter must be repeated as many times in the record value, so sets, whose
+// elements are unique, never equal repeated values.
+type multisetFilterValue []interface{}
+
+// Reports whether the elements of the list or set value are the filter values in any
+// order. Elements are compared the same way as by the in mode.
+func (m multisetFilterValue) equals(s *schema.Schema, value interface{}) bool {
+	var elements []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		elements = v
+	case *schema.Set:
+		elements = v.List()
+	}
+	if len(elements) != len(m) {
+		return false
+	}
+	elemSchema := s.Elem.(*schema.Schema)
+	matched := make([]bool, len(m))
+	for _, element := range elements {
+		found := false
+		for i, filterValue := range m {
+			if !matched[i] && valueMatches(elemSchema, element, filterValue, "in") {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
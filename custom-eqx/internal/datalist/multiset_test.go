@@ -0,0 +1,76 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_setEquals(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name":   {Type: schema.TypeString},
+		"speeds": {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}},
+		"ports":  {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeInt}},
+	}
+	speeds := func(values ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, values)
+	}
+	records := []map[string]interface{}{
+		{"name": "equal", "speeds": speeds("10G", "1G"), "ports": []interface{}{2, 1}},
+		{"name": "superset", "speeds": speeds("1G", "10G", "100G"), "ports": []interface{}{1, 2, 2}},
+		{"name": "subset", "speeds": speeds("1G"), "ports": []interface{}{1}},
+		{"name": "empty", "speeds": speeds(), "ports": []interface{}{}},
+	}
+	testCases := []struct {
+		name         string
+		attribute    string
+		values       []interface{}
+		expectations []string
+	}{
+		{"Set", "speeds", []interface{}{"1G", "10G"}, []string{"equal"}},
+		{"SetRepeatedValues", "speeds", []interface{}{"1G", "1G"}, nil},
+		{"List", "ports", []interface{}{"1", "2"}, []string{"equal"}},
+		{"ListRepeatedValues", "ports", []interface{}{"2", "1", "2"}, []string{"superset"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{
+					"attribute": testCase.attribute,
+					"values":    testCase.values,
+					"match_by":  matchBySetEquals,
+				},
+			})
+			assert.NoError(t, err)
+			// when
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			// then
+			assert.Equal(t, testCase.expectations, names, "Only elements equal to the values in any order match")
+		})
+	}
+}
+
+func TestExpandFilters_setEqualsInvalid(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name":   {Type: schema.TypeString},
+		"speeds": {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	testCases := map[string]map[string]interface{}{
+		"String":  {"attribute": "name", "values": []interface{}{"a"}, "match_by": matchBySetEquals},
+		"AtLeast": {"attribute": "speeds", "values": []interface{}{"1G"}, "match_by": matchBySetEquals, "at_least": 1},
+	}
+	for name, rawFilter := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// when
+			_, err := expandFilters(recordSchema, []interface{}{rawFilter})
+			// then
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package datalist
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Replaces the elements of the set attributes of the flattened record with their
+// normalized values. Elements normalized to the same value are merged, as the sets
+// are rebuilt with the hash function of the original ones.
+func normalizeSetElements(recordSchema map[string]*schema.Schema, record map[string]interface{}, normalize func(attribute string, element interface{}) interface{}) {
+	for attr, s := range recordSchema {
+		if s.Type != schema.TypeSet {
+			continue
+		}
+		set, ok := record[attr].(*schema.Set)
+		if !ok {
+			continue
+		}
+		hash := set.F
+		if hash == nil {
+			hash = schema.HashSchema(s.Elem.(*schema.Schema))
+		}
+		elements := set.List()
+		normalized := make([]interface{}, len(elements))
+		for i, element := range elements {
+			normalized[i] = normalize(attr, element)
+		}
+		record[attr] = schema.NewSet(hash, normalized)
+	}
+}
@@ -0,0 +1,94 @@
+package datalist
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testNormalizeResource(normalize func(attribute string, element interface{}) interface{}) *schema.Resource {
+	return newTestResource(map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+		"tags": {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}},
+	}, []interface{}{
+		map[string]interface{}{"name": "dev-1", "tags": []interface{}{"Prod", " prod"}},
+		map[string]interface{}{"name": "dev-2", "tags": []interface{}{"PROD "}},
+		map[string]interface{}{"name": "dev-3", "tags": []interface{}{"dev"}},
+	}, func(config *ResourceConfig) {
+		config.FlattenRecord = func(record, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+			r := record.(map[string]interface{})
+			return map[string]interface{}{
+				"name": r["name"],
+				"tags": schema.NewSet(schema.HashString, r["tags"].([]interface{})),
+			}, nil
+		}
+		config.NormalizeSetElement = normalize
+	})
+}
+
+func TestNewResource_normalizeSetElement(t *testing.T) {
+	normalize := func(attribute string, element interface{}) interface{} {
+		return strings.ToLower(strings.TrimSpace(element.(string)))
+	}
+	filter := []interface{}{
+		map[string]interface{}{"attribute": "tags", "values": []interface{}{"^prod$"}, "match_by": "re"},
+	}
+	testCases := []struct {
+		name      string
+		normalize func(attribute string, element interface{}) interface{}
+		raw       map[string]interface{}
+		expected  []string
+	}{
+		{"Normalized", normalize, map[string]interface{}{"filter": filter}, []string{"dev-1", "dev-2"}},
+		{"NormalizedDistinct", normalize, map[string]interface{}{"filter": filter, "distinct_by": "tags"}, []string{"dev-1"}},
+		{"NotNormalized", nil, map[string]interface{}{"filter": filter}, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resource := testNormalizeResource(testCase.normalize)
+			d := schema.TestResourceDataRaw(t, resource.Schema, testCase.raw)
+
+			diags := resource.ReadContext(context.Background(), d, nil)
+
+			assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+			var names []string
+			for _, device := range d.Get("devices").([]interface{}) {
+				names = append(names, device.(map[string]interface{})["name"].(string))
+			}
+			assert.Equal(t, testCase.expected, names)
+		})
+	}
+}
+
+func TestNormalizeSetElements(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"tags": {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	record := map[string]interface{}{
+		"tags": schema.NewSet(schema.HashString, []interface{}{"Prod", " prod", "PROD ", "Dev"}),
+	}
+	// when
+	normalizeSetElements(recordSchema, record, func(attribute string, element interface{}) interface{} {
+		return strings.ToLower(strings.TrimSpace(element.(string)))
+	})
+	// then
+	assert.ElementsMatch(t, []interface{}{"prod", "dev"}, record["tags"].(*schema.Set).List(), "Elements equal under the normalizer are merged")
+}
+
+func TestDistinctRecords_sets(t *testing.T) {
+	// given
+	records := []map[string]interface{}{
+		{"name": "dev-1", "tags": schema.NewSet(schema.HashString, []interface{}{"a", "b"})},
+		{"name": "dev-2", "tags": schema.NewSet(schema.HashString, []interface{}{"b", "a"})},
+		{"name": "dev-3", "tags": schema.NewSet(schema.HashString, []interface{}{"a"})},
+	}
+	// when
+	distinct := distinctRecords(records, "tags")
+	// then
+	assert.Equal(t, []string{"dev-1", "dev-3"}, recordNames(distinct), "Sets with the same elements are duplicates")
+}
@@ -0,0 +1,82 @@
+package datalist
+
+import (
+	"context"
+)
+
+// DefaultPageSize is the number of records requested per page when no page size is given.
+const DefaultPageSize = 100
+
+// PageFunc fetches a single page of at most limit records, starting at the given offset.
+// Along with the records it returns the total number of records, or a negative number
+// when the total is not known.
+type PageFunc func(ctx context.Context, offset, limit int) ([]interface{}, int, error)
+
+// ProgressFunc is called after each page is processed with the number of records
+// fetched so far and the total number of records reported with the first page, or a
+// negative number when the total is not known.
+type ProgressFunc func(fetched, total int)
+
+type progressFuncKey struct{}
+
+// WithProgressFunc returns a context reporting the progress of StreamPages and ListAll
+// to the given function. A nil function reports nothing.
+func WithProgressFunc(ctx context.Context, progress ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressFuncKey{}, progress)
+}
+
+func progressFuncFrom(ctx context.Context) ProgressFunc {
+	progress, _ := ctx.Value(progressFuncKey{}).(ProgressFunc)
+	return progress
+}
+
+// StreamPages fetches consecutive pages of records and passes each of them to the callback,
+// so that the records can be processed without holding all of them in memory. Fetching
+// stops after the last page, or as soon as the callback returns an error or the context
+// is done, in which case the error is returned. The progress is reported after each page
+// to the ProgressFunc of the context, if any.
+func StreamPages(ctx context.Context, pageSize int, fetch PageFunc, callback func(page []interface{}) error) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	progress := progressFuncFrom(ctx)
+	firstTotal := -1
+	offset := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, total, err := fetch(ctx, offset, pageSize)
+		if err != nil {
+			return err
+		}
+		if err := callback(page); err != nil {
+			return err
+		}
+		if offset == 0 {
+			firstTotal = total
+		}
+		offset += len(page)
+		if progress != nil {
+			progress(offset, firstTotal)
+		}
+
+		if len(page) == 0 || (total >= 0 && offset >= total) || (total < 0 && len(page) < pageSize) {
+			return nil
+		}
+	}
+}
+
+// ListAll fetches all pages of records and returns them as a single slice.
+func ListAll(ctx context.Context, pageSize int, fetch PageFunc) ([]interface{}, error) {
+	var records []interface{}
+	err := StreamPages(ctx, pageSize, fetch, func(page []interface{}) error {
+		records = append(records, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,181 @@
+package datalist
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPager struct {
+	records   []interface{}
+	knowTotal bool
+	fetches   int
+}
+
+func newTestPager(count int, knowTotal bool) *testPager {
+	records := make([]interface{}, count)
+	for i := range records {
+		records[i] = i
+	}
+	return &testPager{records: records, knowTotal: knowTotal}
+}
+
+func (p *testPager) fetch(ctx context.Context, offset, limit int) ([]interface{}, int, error) {
+	p.fetches++
+	total := -1
+	if p.knowTotal {
+		total = len(p.records)
+	}
+	if offset >= len(p.records) {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > len(p.records) {
+		end = len(p.records)
+	}
+	return p.records[offset:end], total, nil
+}
+
+func (p *testPager) getRecordsPage(ctx context.Context, meta interface{}, extra map[string]interface{}, offset, limit int) ([]interface{}, int, error) {
+	return p.fetch(ctx, offset, limit)
+}
+
+func TestStreamPages(t *testing.T) {
+	testCases := []struct {
+		name            string
+		count           int
+		knowTotal       bool
+		expectedFetches int
+	}{
+		{"KnownTotal", 25, true, 3},
+		{"KnownTotalFullPages", 20, true, 2},
+		{"UnknownTotal", 25, false, 3},
+		{"UnknownTotalFullPages", 20, false, 3},
+		{"Empty", 0, true, 1},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pager := newTestPager(testCase.count, testCase.knowTotal)
+			var seen []interface{}
+			err := StreamPages(context.Background(), 10, pager.fetch, func(page []interface{}) error {
+				assert.LessOrEqual(t, len(page), 10)
+				seen = append(seen, page...)
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.count, len(seen))
+			for i := range seen {
+				assert.Equal(t, i, seen[i])
+			}
+			assert.Equal(t, testCase.expectedFetches, pager.fetches)
+		})
+	}
+}
+
+func TestStreamPages_callbackError(t *testing.T) {
+	pager := newTestPager(50, true)
+	callbackErr := errors.New("stop")
+	pages := 0
+	err := StreamPages(context.Background(), 10, pager.fetch, func(page []interface{}) error {
+		pages++
+		if pages == 2 {
+			return callbackErr
+		}
+		return nil
+	})
+	assert.Equal(t, callbackErr, err)
+	assert.Equal(t, 2, pager.fetches)
+}
+
+func TestStreamPages_contextCanceled(t *testing.T) {
+	pager := newTestPager(50, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	err := StreamPages(ctx, 10, pager.fetch, func(page []interface{}) error {
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, pager.fetches)
+}
+
+func TestListAll(t *testing.T) {
+	pager := newTestPager(250, false)
+	records, err := ListAll(context.Background(), 0, pager.fetch)
+	assert.NoError(t, err)
+	assert.Len(t, records, 250)
+	assert.Equal(t, 3, pager.fetches)
+}
+
+func TestNewResource_getRecordsPage(t *testing.T) {
+	pager := newTestPager(25, true)
+	resource := newTestNumbersResource(10, pager.getRecordsPage, nil)
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{
+				"attribute": "number",
+				"values":    []interface{}{"20"},
+				"match_by":  "greater_than_or_equal",
+			},
+		},
+	})
+
+	diags := resource.ReadContext(context.Background(), d, nil)
+
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	assert.Equal(t, 3, pager.fetches)
+	numbers := d.Get("numbers").([]interface{})
+	assert.Len(t, numbers, 5)
+	assert.Equal(t, 20, numbers[0].(map[string]interface{})["number"])
+}
+
+func TestStreamPages_progress(t *testing.T) {
+	testCases := []struct {
+		name      string
+		knowTotal bool
+		expected  [][2]int
+	}{
+		{"KnownTotal", true, [][2]int{{10, 25}, {20, 25}, {25, 25}}},
+		{"UnknownTotal", false, [][2]int{{10, -1}, {20, -1}, {25, -1}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pager := newTestPager(25, testCase.knowTotal)
+			var reports [][2]int
+			ctx := WithProgressFunc(context.Background(), func(fetched, total int) {
+				reports = append(reports, [2]int{fetched, total})
+			})
+
+			_, err := ListAll(ctx, 10, pager.fetch)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, reports, "Progress is reported with increasing counts")
+		})
+	}
+
+	_, err := ListAll(WithProgressFunc(context.Background(), nil), 10, newTestPager(5, true).fetch)
+	assert.NoError(t, err, "Nil progress function is ignored")
+}
+
+func TestNewResource_progressFunc(t *testing.T) {
+	// given
+	type progressReport struct{ fetched, total int }
+	var reports []progressReport
+	resource := newTestNumbersResource(2, newTestPager(5, true).getRecordsPage, func(config *ResourceConfig) {
+		config.ProgressFunc = func(meta interface{}) ProgressFunc {
+			return func(fetched, total int) {
+				reports = append(reports, progressReport{fetched, total})
+			}
+		}
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	assert.Equal(t, []progressReport{{2, 5}, {4, 5}, {5, 5}}, reports, "Progress is reported after each page")
+}
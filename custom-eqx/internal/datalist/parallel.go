@@ -0,0 +1,59 @@
+package datalist
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Applies the filter expression to the records with up to workers goroutines, each
+// evaluating a contiguous chunk of the records, and returns the matching records in
+// their original order. The records are filtered sequentially with fewer than two
+// workers. Filtering stops with the error of the context once it is done.
+func applyFilterExpressionParallel(ctx context.Context, recordSchema map[string]*schema.Schema, records []map[string]interface{}, expression filterExpression, workers int) ([]map[string]interface{}, error) {
+	if workers < 2 || len(records) < 2 {
+		var filteredRecords []map[string]interface{}
+		for _, record := range records {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if expression.matches(recordSchema, record) {
+				filteredRecords = append(filteredRecords, record)
+			}
+		}
+		return filteredRecords, nil
+	}
+
+	chunkSize := (len(records) + workers - 1) / workers
+	matched := make([]bool, len(records))
+	var wg sync.WaitGroup
+	for start := 0; start < len(records); start += chunkSize {
+		end := start + chunkSize
+		if end > len(records) {
+			end = len(records)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				if ctx.Err() != nil {
+					return
+				}
+				matched[i] = expression.matches(recordSchema, records[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var filteredRecords []map[string]interface{}
+	for i, record := range records {
+		if matched[i] {
+			filteredRecords = append(filteredRecords, record)
+		}
+	}
+	return filteredRecords, nil
+}
@@ -0,0 +1,77 @@
+package datalist
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func parallelTestSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name":  {Type: schema.TypeString},
+		"cores": {Type: schema.TypeInt},
+	}
+}
+
+func parallelTestData(count int) []map[string]interface{} {
+	records := make([]map[string]interface{}, count)
+	for i := range records {
+		records[i] = map[string]interface{}{"name": fmt.Sprintf("dev-%d", i), "cores": i % 8}
+	}
+	return records
+}
+
+func parallelTestExpression(t testing.TB) filterExpression {
+	filters, err := expandFilters(parallelTestSchema(), []interface{}{
+		map[string]interface{}{"attribute": "name", "values": []interface{}{`^dev-\d*[137]$`}, "match_by": "re"},
+		map[string]interface{}{"attribute": "cores", "values": []interface{}{"2"}, "match_by": "greater_than"},
+	})
+	if err != nil {
+		t.Fatalf("expandFilters returned error: %s", err)
+	}
+	return compileFilters(filters)
+}
+
+func TestApplyFilterExpressionParallel(t *testing.T) {
+	// given
+	records := parallelTestData(1001)
+	expression := parallelTestExpression(t)
+	expected := applyFilterExpression(parallelTestSchema(), records, expression)
+	for _, workers := range []int{0, 1, 2, 7, 2000} {
+		// when
+		filtered, err := applyFilterExpressionParallel(context.Background(), parallelTestSchema(), records, expression, workers)
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, expected, filtered, "Records filtered by %d workers match the sequential filtering, in order", workers)
+	}
+}
+
+func TestApplyFilterExpressionParallel_canceled(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, workers := range []int{1, 4} {
+		// when
+		filtered, err := applyFilterExpressionParallel(ctx, parallelTestSchema(), parallelTestData(100), parallelTestExpression(t), workers)
+		// then
+		assert.ErrorIs(t, err, context.Canceled, "Filtering by %d workers stops once the context is canceled", workers)
+		assert.Nil(t, filtered)
+	}
+}
+
+func BenchmarkApplyFilterExpressionParallel(b *testing.B) {
+	records := parallelTestData(100000)
+	expression := parallelTestExpression(b)
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := applyFilterExpressionParallel(context.Background(), parallelTestSchema(), records, expression, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package datalist
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// PushdownQueryKey is the key of the `extra` map under which the record getters
+// receive the API query parameters translated from the pushed down filters, as
+// url.Values.
+const PushdownQueryKey = "__pushdown_query"
+
+// DefaultSortQueryParameter is the API query parameter carrying the sort key of
+// `server_sort`, unless ResourceConfig.SortQueryParameter is set.
+const DefaultSortQueryParameter = "sort"
+
+// Splits the filters into API query parameters, for the filters which can be pushed
+// down, and the remaining filters which are applied to the loaded records. The pushed
+// down filters are returned as well. A filter is pushed down when its attribute is
+// mapped to a query parameter, it uses the `in` mode and it has a single value, which
+// is neither transformed, compared with a tolerance nor counted.
+func pushdownFilters(queryParameters map[string]string, filters []commonFilter) (url.Values, []commonFilter, []commonFilter) {
+	query := url.Values{}
+	var pushed, remaining []commonFilter
+	for _, f := range filters {
+		param, ok := queryParameters[f.attribute]
+		if !ok || f.matchBy != "in" || len(f.values) != 1 || isTransformed(f) || hasTolerance(f) || f.count != nil || query.Get(param) != "" {
+			remaining = append(remaining, f)
+			continue
+		}
+		query.Set(param, fmt.Sprint(f.values[0]))
+		pushed = append(pushed, f)
+	}
+	return query, pushed, remaining
+}
+
+// Returns the API query parameter and sort key ordering the records by the attribute,
+// or false when the API cannot sort on it.
+func serverSortQuery(config *ResourceConfig, attribute string) (string, string, bool) {
+	key, ok := config.SortKeys[attribute]
+	if !ok {
+		return "", "", false
+	}
+	param := config.SortQueryParameter
+	if param == "" {
+		param = DefaultSortQueryParameter
+	}
+	return param, key, true
+}
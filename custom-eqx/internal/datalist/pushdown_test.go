@@ -0,0 +1,140 @@
+package datalist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResource_pushdown(t *testing.T) {
+	var receivedQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedQuery = r.URL.Query()
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"name": "dev-1", "metro_code": "SV", "status": "PROVISIONED", "cores": 2},
+			{"name": "dev-2", "metro_code": "SV", "status": "PROVISIONED", "cores": 4},
+		})
+	}))
+	defer server.Close()
+	resource := newTestResource(map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+		"status":     {Type: schema.TypeString},
+		"cores":      {Type: schema.TypeInt},
+	}, nil, func(config *ResourceConfig) {
+		config.FlattenRecord = func(record, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+			flattened := record.(map[string]interface{})
+			flattened["cores"] = int(flattened["cores"].(float64))
+			return flattened, nil
+		}
+		config.GetRecords = func(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+			query := extra[PushdownQueryKey].(url.Values)
+			resp, err := http.Get(server.URL + "/devices?" + query.Encode())
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			var records []interface{}
+			err = json.NewDecoder(resp.Body).Decode(&records)
+			return records, err
+		}
+		config.QueryParameters = map[string]string{
+			"metro_code": "metroCode",
+			"status":     "status",
+			"cores":      "cores",
+		}
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{
+				"attribute": "metro_code",
+				"values":    []interface{}{"SV"},
+			},
+			map[string]interface{}{
+				"attribute": "status",
+				"values":    []interface{}{"PROVISIONED", "REGISTERED"},
+			},
+			map[string]interface{}{
+				"attribute": "cores",
+				"values":    []interface{}{"4"},
+				"match_by":  "greater_than_or_equal",
+			},
+		},
+	})
+
+	diags := resource.ReadContext(context.Background(), d, nil)
+
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	assert.Equal(t, url.Values{"metroCode": {"SV"}}, receivedQuery, "Only single value `in` filters are pushed down")
+	devices := d.Get("devices").([]interface{})
+	assert.Len(t, devices, 1, "Remaining filters are applied client-side")
+	assert.Equal(t, "dev-2", devices[0].(map[string]interface{})["name"])
+}
+
+func TestNewResource_serverSort(t *testing.T) {
+	testCases := []struct {
+		name            string
+		attribute       string
+		expectedQueries []url.Values
+		expected        []string
+	}{
+		{
+			"Supported",
+			"name",
+			[]url.Values{{"orderBy": {"NAME"}}},
+			[]string{"dev-3", "dev-1"},
+		},
+		{
+			"Unsupported",
+			"created_date",
+			[]url.Values{{}, {}},
+			[]string{"dev-2", "dev-1"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var queries []url.Values
+			resource := newTestResource(map[string]*schema.Schema{
+				"name":         {Type: schema.TypeString},
+				"created_date": {Type: schema.TypeString},
+			}, nil, func(config *ResourceConfig) {
+				withTestPages(config, 2, func(ctx context.Context, meta interface{}, extra map[string]interface{}, offset, limit int) ([]interface{}, int, error) {
+					queries = append(queries, extra[PushdownQueryKey].(url.Values))
+					pages := [][]interface{}{
+						{
+							map[string]interface{}{"name": "dev-3", "created_date": "2022-12-03T00:00:00Z"},
+							map[string]interface{}{"name": "dev-1", "created_date": "2022-12-02T00:00:00Z"},
+						},
+						{
+							map[string]interface{}{"name": "dev-2", "created_date": "2022-12-01T00:00:00Z"},
+						},
+					}
+					return pages[offset/limit], 3, nil
+				})
+				config.SortKeys = map[string]string{"name": "NAME"}
+				config.SortQueryParameter = "orderBy"
+			})
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+				"server_sort": testCase.attribute,
+				"limit":       2,
+			})
+
+			diags := resource.ReadContext(context.Background(), d, nil)
+
+			assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+			assert.Equal(t, testCase.expectedQueries, queries)
+			var names []string
+			for _, device := range d.Get("devices").([]interface{}) {
+				names = append(names, device.(map[string]interface{})["name"].(string))
+			}
+			assert.Equal(t, testCase.expected, names)
+		})
+	}
+}
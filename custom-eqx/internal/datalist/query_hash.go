@@ -0,0 +1,88 @@
+package datalist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func queryHashSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The SHA-256 hash, in hexadecimal, of the query arguments of the data source, such as filter, sort and limit. Queries only differing by the order of their filters or filter values, which does not change the results, have the same hash, which can key cached results",
+		Computed:    true,
+	}
+}
+
+// Returns the hash of the query arguments, keyed by attribute name. The arguments are
+// normalized first, so that semantically identical queries share their hash: filters,
+// which are joined with an AND, and their values, which are joined with an OR or an
+// AND, are sorted, the filter expression is canonicalized and sets are sorted.
+func queryHash(query map[string]interface{}) (string, error) {
+	normalized := make(map[string]interface{}, len(query))
+	for attr, value := range query {
+		normalized[attr] = fingerprintValue(value)
+	}
+	if filters, ok := query["filter"].(*schema.Set); ok {
+		normalizedFilters, err := normalizeFilterSpecs(filters.List())
+		if err != nil {
+			return "", err
+		}
+		normalized["filter"] = normalizedFilters
+	}
+	if expression, ok := normalized["filter_expression"].(string); ok && expression != "" {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(expression), &decoded); err != nil {
+			return "", fmt.Errorf("unable to parse filter expression: %s", err)
+		}
+		normalized["filter_expression"] = decoded
+	}
+
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash query: %s", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func normalizeFilterSpecs(filters []interface{}) ([]string, error) {
+	encodedFilters := make([]string, len(filters))
+	for i, rawFilter := range filters {
+		filter := map[string]interface{}{}
+		for k, v := range rawFilter.(map[string]interface{}) {
+			filter[k] = v
+		}
+		if values, ok := filter["values"].([]interface{}); ok {
+			sortedValues := make([]string, len(values))
+			for j, value := range values {
+				sortedValues[j] = fmt.Sprint(value)
+			}
+			sort.Strings(sortedValues)
+			filter["values"] = sortedValues
+		}
+		encoded, err := json.Marshal(filter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to hash filter: %s", err)
+		}
+		encodedFilters[i] = string(encoded)
+	}
+	sort.Strings(encodedFilters)
+	return encodedFilters, nil
+}
+
+// Returns the optional attributes of the data source schema, which make its query.
+func queryAttributes(datasourceSchema map[string]*schema.Schema) []string {
+	var attributes []string
+	for attr, s := range datasourceSchema {
+		if s.Optional {
+			attributes = append(attributes, attr)
+		}
+	}
+	sort.Strings(attributes)
+	return attributes
+}
@@ -0,0 +1,83 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResource_queryHash(t *testing.T) {
+	// given
+	resource := newTestResource(map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+		"status":     {Type: schema.TypeString},
+	}, nil, nil)
+	configs := map[string]map[string]interface{}{
+		"base": {
+			"filter": []interface{}{
+				map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"SV", "DC"}},
+				map[string]interface{}{"attribute": "status", "values": []interface{}{"PROVISIONED"}},
+			},
+			"filter_expression": `{"attribute": "name", "values": ["dev-1"]}`,
+			"limit":             5,
+		},
+		"equivalent": {
+			"filter": []interface{}{
+				map[string]interface{}{"attribute": "status", "values": []interface{}{"PROVISIONED"}, "match_by": "in"},
+				map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"DC", "SV"}},
+			},
+			"filter_expression": `{ "values": ["dev-1"], "attribute": "name" }`,
+			"limit":             5,
+		},
+		"changedFilter": {
+			"filter": []interface{}{
+				map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"SV", "AM"}},
+				map[string]interface{}{"attribute": "status", "values": []interface{}{"PROVISIONED"}},
+			},
+			"filter_expression": `{"attribute": "name", "values": ["dev-1"]}`,
+			"limit":             5,
+		},
+		"changedLimit": {
+			"filter": []interface{}{
+				map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"SV", "DC"}},
+				map[string]interface{}{"attribute": "status", "values": []interface{}{"PROVISIONED"}},
+			},
+			"filter_expression": `{"attribute": "name", "values": ["dev-1"]}`,
+			"limit":             6,
+		},
+	}
+	// when
+	hashes := map[string]string{}
+	for name, raw := range configs {
+		d := schema.TestResourceDataRaw(t, resource.Schema, raw)
+		diags := resource.ReadContext(context.Background(), d, nil)
+		assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+		hashes[name] = d.Get("query_hash").(string)
+	}
+	// then
+	assert.Len(t, hashes["base"], 64, "Hash is a hexadecimal SHA-256 hash")
+	assert.Equal(t, hashes["base"], hashes["equivalent"], "Equivalent queries have the same hash")
+	assert.NotEqual(t, hashes["base"], hashes["changedFilter"], "Changing a filter changes the hash")
+	assert.NotEqual(t, hashes["base"], hashes["changedLimit"], "Changing the limit changes the hash")
+}
+
+func TestQueryHash_sortOrder(t *testing.T) {
+	// given
+	ascending := map[string]interface{}{"sort": []interface{}{
+		map[string]interface{}{"attribute": "name", "direction": "asc"},
+		map[string]interface{}{"attribute": "metro_code", "direction": "asc"},
+	}}
+	reordered := map[string]interface{}{"sort": []interface{}{
+		map[string]interface{}{"attribute": "metro_code", "direction": "asc"},
+		map[string]interface{}{"attribute": "name", "direction": "asc"},
+	}}
+	// when
+	hash, err := queryHash(ascending)
+	reorderedHash, _ := queryHash(reordered)
+	// then
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, reorderedHash, "The order of the sorts is significant")
+}
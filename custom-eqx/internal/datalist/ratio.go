@@ -0,0 +1,132 @@
+package datalist
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	expressionRatio = "ratio"
+
+	// Ratios with a zero denominator do not match
+	ratioZeroSkip = "skip"
+	// Ratios with a zero denominator are treated as 0
+	ratioZeroAsZero = "zero"
+)
+
+// ratioFilter compares the ratio of two numeric attributes of a record with a threshold.
+type ratioFilter struct {
+	numerator   string
+	denominator string
+	matchBy     string
+	threshold   float64
+	onZero      string
+}
+
+// Parses a `ratio` filter expression node, e.g.
+// {"numerator": "used", "denominator": "provisioned", "match_by": "greater_than", "value": 0.8}.
+// The optional `on_zero_denominator` key is either "skip" (default) or "zero".
+func expandRatioFilter(recordSchema map[string]*schema.Schema, raw interface{}) (*ratioFilter, error) {
+	node, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%q filter expression node must be an object", expressionRatio)
+	}
+	f := &ratioFilter{matchBy: "in", onZero: ratioZeroSkip}
+	for k, v := range node {
+		switch k {
+		case "numerator", "denominator":
+			attr, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("ratio filter key %q must be a string", k)
+			}
+			s, ok := recordSchema[attr]
+			if !ok {
+				return nil, fmt.Errorf("field '%s' does not exist in record schema", attr)
+			}
+			if s.Type != schema.TypeInt && s.Type != schema.TypeFloat {
+				return nil, fmt.Errorf("ratio filter field '%s' must be numeric, got: %s", attr, s.Type)
+			}
+			if k == "numerator" {
+				f.numerator = attr
+			} else {
+				f.denominator = attr
+			}
+		case "match_by":
+			matchBy, ok := v.(string)
+			if !ok || (matchBy != "in" && !isNumberComparison(matchBy)) {
+				return nil, fmt.Errorf("ratio filter key %q must be one of: in, less_than, less_than_or_equal, greater_than, greater_than_or_equal", k)
+			}
+			f.matchBy = matchBy
+		case "value":
+			threshold, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("ratio filter key %q must be a number", k)
+			}
+			f.threshold = threshold
+		case "on_zero_denominator":
+			onZero, ok := v.(string)
+			if !ok || (onZero != ratioZeroSkip && onZero != ratioZeroAsZero) {
+				return nil, fmt.Errorf("ratio filter key %q must be one of: %s, %s", k, ratioZeroSkip, ratioZeroAsZero)
+			}
+			f.onZero = onZero
+		default:
+			return nil, fmt.Errorf("unsupported ratio filter key: %q", k)
+		}
+	}
+	if f.numerator == "" || f.denominator == "" {
+		return nil, fmt.Errorf("ratio filter requires both numerator and denominator")
+	}
+	if _, ok := node["value"]; !ok {
+		return nil, fmt.Errorf("ratio filter requires a value")
+	}
+	return f, nil
+}
+
+func isNumberComparison(matchBy string) bool {
+	for _, nc := range matchByNumberComparison {
+		if matchBy == nc {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *ratioFilter) matches(record map[string]interface{}) bool {
+	numerator, ok := numericValue(record[f.numerator])
+	if !ok {
+		return false
+	}
+	denominator, ok := numericValue(record[f.denominator])
+	if !ok {
+		return false
+	}
+	ratio := 0.
+	if denominator != 0 {
+		ratio = numerator / denominator
+	} else if f.onZero == ratioZeroSkip {
+		return false
+	}
+
+	switch f.matchBy {
+	case "less_than":
+		return ratio < f.threshold
+	case "less_than_or_equal":
+		return ratio < f.threshold || floatApproxEquals(ratio, f.threshold)
+	case "greater_than":
+		return ratio > f.threshold
+	case "greater_than_or_equal":
+		return ratio > f.threshold || floatApproxEquals(ratio, f.threshold)
+	}
+	return floatApproxEquals(ratio, f.threshold)
+}
+
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
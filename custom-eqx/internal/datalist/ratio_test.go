@@ -0,0 +1,86 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func ratioTestSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name":        {Type: schema.TypeString},
+		"used":        {Type: schema.TypeInt},
+		"provisioned": {Type: schema.TypeFloat},
+	}
+}
+
+func ratioTestData() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"name": "port-1", "used": 90, "provisioned": 100.},
+		{"name": "port-2", "used": 50, "provisioned": 100.},
+		{"name": "port-3", "used": 80, "provisioned": 100.},
+		{"name": "port-4", "used": 0, "provisioned": 0.},
+	}
+}
+
+func TestApplyFilterExpression_ratio(t *testing.T) {
+	testCases := []struct {
+		name          string
+		expression    string
+		expectedNames []string
+	}{
+		{
+			"GreaterThan",
+			`{"ratio": {"numerator": "used", "denominator": "provisioned", "match_by": "greater_than", "value": 0.8}}`,
+			[]string{"port-1"},
+		},
+		{
+			"GreaterThanOrEqual",
+			`{"ratio": {"numerator": "used", "denominator": "provisioned", "match_by": "greater_than_or_equal", "value": 0.8}}`,
+			[]string{"port-1", "port-3"},
+		},
+		{
+			"ZeroDenominatorSkipped",
+			`{"ratio": {"numerator": "used", "denominator": "provisioned", "match_by": "less_than", "value": 0.6}}`,
+			[]string{"port-2"},
+		},
+		{
+			"ZeroDenominatorAsZero",
+			`{"ratio": {"numerator": "used", "denominator": "provisioned", "match_by": "less_than", "value": 0.6, "on_zero_denominator": "zero"}}`,
+			[]string{"port-2", "port-4"},
+		},
+		{
+			"Combined",
+			`{"and": [{"attribute": "name", "values": ["port-1", "port-2"]}, {"not": {"ratio": {"numerator": "used", "denominator": "provisioned", "match_by": "greater_than", "value": 0.8}}}]}`,
+			[]string{"port-2"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expression, err := expandFilterExpression(ratioTestSchema(), testCase.expression)
+			if err != nil {
+				t.Fatalf("expandFilterExpression returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilterExpression(ratioTestSchema(), ratioTestData(), expression) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectedNames, names)
+		})
+	}
+}
+
+func TestExpandFilterExpression_invalidRatio(t *testing.T) {
+	for _, expression := range []string{
+		`{"ratio": {"numerator": "name", "denominator": "provisioned", "value": 1}}`,
+		`{"ratio": {"numerator": "used", "value": 1}}`,
+		`{"ratio": {"numerator": "used", "denominator": "provisioned"}}`,
+		`{"ratio": {"numerator": "used", "denominator": "provisioned", "value": 1, "match_by": "re"}}`,
+		`{"ratio": {"numerator": "used", "denominator": "provisioned", "value": 1, "on_zero_denominator": "one"}}`,
+	} {
+		_, err := expandFilterExpression(ratioTestSchema(), expression)
+		assert.Error(t, err, "expression %s", expression)
+	}
+}
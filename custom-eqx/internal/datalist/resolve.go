@@ -0,0 +1,76 @@
+package datalist
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// The resolver looking up the hostnames of the resolves and not_resolves match modes,
+// replaced in tests.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// The time limit of each hostname lookup.
+const hostnameLookupTimeout = 5 * time.Second
+
+// hostnameResolver is the filter value of the resolves and not_resolves match modes.
+// It caches the outcome of the lookups of each hostname, so that a hostname is only
+// looked up once per read, and stops looking up hostnames once its context is done.
+type hostnameResolver struct {
+	ctx      context.Context
+	mu       sync.Mutex
+	resolved map[string]bool
+}
+
+func newHostnameResolver(ctx context.Context) *hostnameResolver {
+	return &hostnameResolver{ctx: ctx, resolved: map[string]bool{}}
+}
+
+// Reports whether the hostname resolves to at least one address. Hostnames are
+// considered not to resolve once the context of the resolver is done.
+func (r *hostnameResolver) resolves(hostname string) bool {
+	if hostname == "" {
+		return false
+	}
+	r.mu.Lock()
+	resolved, ok := r.resolved[hostname]
+	r.mu.Unlock()
+	if ok {
+		return resolved
+	}
+	if r.ctx.Err() != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(r.ctx, hostnameLookupTimeout)
+	defer cancel()
+	addrs, err := lookupHost(ctx, hostname)
+	resolved = err == nil && len(addrs) > 0
+	// Lookups interrupted by the context are not cached, as they are inconclusive
+	if r.ctx.Err() == nil {
+		r.mu.Lock()
+		r.resolved[hostname] = resolved
+		r.mu.Unlock()
+	}
+	return resolved
+}
+
+// Sets the resolver shared by the resolves and not_resolves filters of the expression,
+// which caches their lookups for the read and bounds them to its context.
+func (e filterExpression) setHostnameResolver(resolver *hostnameResolver) {
+	for _, child := range e.children {
+		child.setHostnameResolver(resolver)
+	}
+	if e.filter == nil || (e.filter.matchBy != "resolves" && e.filter.matchBy != "not_resolves") {
+		return
+	}
+	for i, value := range e.filter.values {
+		if v, ok := value.(transformedFilterValue); ok {
+			v.value = resolver
+			e.filter.values[i] = v
+			continue
+		}
+		e.filter.values[i] = resolver
+	}
+}
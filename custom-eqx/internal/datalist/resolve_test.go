@@ -0,0 +1,74 @@
+package datalist
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResource_resolves(t *testing.T) {
+	// given
+	var mu sync.Mutex
+	lookups := map[string]int{}
+	defer func(lookup func(context.Context, string) ([]string, error)) { lookupHost = lookup }(lookupHost)
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		mu.Lock()
+		lookups[host]++
+		mu.Unlock()
+		if host == "router.example.com" {
+			return []string{"192.0.2.10"}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	resource := newTestResource(map[string]*schema.Schema{
+		"name":     {Type: schema.TypeString},
+		"hostname": {Type: schema.TypeString},
+	}, []interface{}{
+		map[string]interface{}{"name": "dev-1", "hostname": "router.example.com"},
+		map[string]interface{}{"name": "dev-2", "hostname": "stale.example.com"},
+		map[string]interface{}{"name": "dev-3", "hostname": "router.example.com"},
+		map[string]interface{}{"name": "dev-4", "hostname": ""},
+	}, nil)
+	for matchBy, expected := range map[string][]string{
+		"resolves":     {"dev-1", "dev-3"},
+		"not_resolves": {"dev-2", "dev-4"},
+	} {
+		lookups = map[string]int{}
+		d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+			"filter": []interface{}{
+				map[string]interface{}{"attribute": "hostname", "match_by": matchBy},
+			},
+		})
+		// when
+		diags := resource.ReadContext(context.Background(), d, nil)
+		// then
+		assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+		var names []string
+		for _, device := range d.Get("devices").([]interface{}) {
+			names = append(names, device.(map[string]interface{})["name"].(string))
+		}
+		assert.Equal(t, expected, names, "match_by %s", matchBy)
+		assert.Equal(t, map[string]int{"router.example.com": 1, "stale.example.com": 1}, lookups, "Hostnames are looked up once per read")
+	}
+}
+
+func TestHostnameResolver_canceled(t *testing.T) {
+	// given
+	defer func(lookup func(context.Context, string) ([]string, error)) { lookupHost = lookup }(lookupHost)
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		t.Errorf("unexpected lookup of %s", host)
+		return []string{"192.0.2.10"}, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resolver := newHostnameResolver(ctx)
+	// when
+	resolved := resolver.resolves("router.example.com")
+	// then
+	assert.False(t, resolved, "Hostnames are not looked up once the context is done")
+	assert.Empty(t, resolver.resolved, "Inconclusive lookups are not cached")
+}
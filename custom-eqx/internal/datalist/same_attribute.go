@@ -0,0 +1,62 @@
+package datalist
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	sameAttributeFiltersAnd = "and"
+	sameAttributeFiltersOr  = "or"
+)
+
+func sameAttributeFiltersSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  "How `filter` blocks on the same attribute are joined. One of: and (default), or. With and, all of the filters must match, e.g. a greater_than and a less_than filter select a range. With or, the filters on the same attribute form a group which matches when any of its filters matches, e.g. a name in \"a\" and a name re \"^b-\" filter select both names, while the groups of different attributes are still joined with an AND",
+		Optional:     true,
+		Default:      sameAttributeFiltersAnd,
+		ValidateFunc: validation.StringInSlice([]string{sameAttributeFiltersAnd, sameAttributeFiltersOr}, false),
+	}
+}
+
+// Splits the filters into those whose attribute is not used by any other filter and
+// those sharing their attribute with other filters, keeping their order.
+func partitionSameAttributeFilters(filters []commonFilter) ([]commonFilter, []commonFilter) {
+	counts := map[string]int{}
+	for _, f := range filters {
+		counts[f.attribute]++
+	}
+	var unique, shared []commonFilter
+	for _, f := range filters {
+		if counts[f.attribute] > 1 {
+			shared = append(shared, f)
+		} else {
+			unique = append(unique, f)
+		}
+	}
+	return unique, shared
+}
+
+// Compiles a flat list of filters into an expression tree which joins the filters on
+// the same attribute with an OR, and the groups of different attributes with an AND.
+func compileFilterGroups(filters []commonFilter) filterExpression {
+	var attributes []string
+	groups := map[string][]filterExpression{}
+	for i := range filters {
+		attr := filters[i].attribute
+		if _, ok := groups[attr]; !ok {
+			attributes = append(attributes, attr)
+		}
+		groups[attr] = append(groups[attr], filterExpression{filter: &filters[i]})
+	}
+	children := make([]filterExpression, len(attributes))
+	for i, attr := range attributes {
+		if len(groups[attr]) == 1 {
+			children[i] = groups[attr][0]
+			continue
+		}
+		children[i] = filterExpression{op: expressionOr, children: groups[attr]}
+	}
+	return filterExpression{op: expressionAnd, children: children}
+}
@@ -0,0 +1,108 @@
+package datalist
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func sameAttributeTestResource(queries *[]url.Values) *schema.Resource {
+	return newTestResource(map[string]*schema.Schema{
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+	}, nil, func(config *ResourceConfig) {
+		config.QueryParameters = map[string]string{"name": "name", "metro_code": "metroCode"}
+		config.GetRecords = func(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+			query := extra[PushdownQueryKey].(url.Values)
+			*queries = append(*queries, query)
+			var records []interface{}
+			for _, record := range []map[string]interface{}{
+				{"name": "dev-1", "metro_code": "SV"},
+				{"name": "dev-2", "metro_code": "SV"},
+				{"name": "dev-3", "metro_code": "DC"},
+			} {
+				if name := query.Get("name"); name != "" && name != record["name"] {
+					continue
+				}
+				if metro := query.Get("metroCode"); metro != "" && metro != record["metro_code"] {
+					continue
+				}
+				records = append(records, record)
+			}
+			return records, nil
+		}
+	})
+}
+
+func TestNewResource_sameAttributeFilters(t *testing.T) {
+	// given
+	filters := []interface{}{
+		map[string]interface{}{"attribute": "name", "values": []interface{}{"dev-1"}},
+		map[string]interface{}{"attribute": "name", "values": []interface{}{"dev-[23]"}, "match_by": "re"},
+		map[string]interface{}{"attribute": "metro_code", "values": []interface{}{"SV"}},
+	}
+	tests := map[string]struct {
+		mode          string
+		expectedNames []interface{}
+	}{
+		"And": {sameAttributeFiltersAnd, nil},
+		"Or":  {sameAttributeFiltersOr, []interface{}{"dev-1", "dev-2"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var queries []url.Values
+			resource := sameAttributeTestResource(&queries)
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+				"filter":                 filters,
+				"same_attribute_filters": tt.mode,
+				"warn_on_empty":          false,
+			})
+			// when
+			diags := resource.ReadContext(context.Background(), d, nil)
+			// then
+			assert.False(t, diags.HasError(), "Read does not return errors")
+			var names []interface{}
+			for _, device := range d.Get("devices").([]interface{}) {
+				names = append(names, device.(map[string]interface{})["name"])
+			}
+			assert.Equal(t, tt.expectedNames, names, "Filters on the same attribute are joined as configured")
+			assert.Equal(t, "SV", queries[0].Get("metroCode"), "Filters on other attributes are pushed down")
+			if tt.mode == sameAttributeFiltersOr {
+				assert.Empty(t, queries[0].Get("name"), "Filters of an OR group are not pushed down")
+			}
+		})
+	}
+}
+
+func TestCompileFilterGroups(t *testing.T) {
+	// given
+	filters := []commonFilter{
+		{attribute: "name", values: []interface{}{"a"}, matchBy: "in"},
+		{attribute: "metro_code", values: []interface{}{"SV"}, matchBy: "in"},
+		{attribute: "name", values: []interface{}{"b"}, matchBy: "in"},
+	}
+	// when
+	expression := compileFilterGroups(filters)
+	// then
+	assert.Equal(t, filterExpression{op: expressionAnd, children: []filterExpression{
+		{op: expressionOr, children: []filterExpression{{filter: &filters[0]}, {filter: &filters[2]}}},
+		{filter: &filters[1]},
+	}}, expression, "Filters on the same attribute are grouped with an OR")
+}
+
+func TestPartitionSameAttributeFilters(t *testing.T) {
+	// given
+	filters := []commonFilter{
+		{attribute: "name", matchBy: "in"},
+		{attribute: "metro_code", matchBy: "in"},
+		{attribute: "name", matchBy: "re"},
+	}
+	// when
+	unique, shared := partitionSameAttributeFilters(filters)
+	// then
+	assert.Equal(t, []commonFilter{filters[1]}, unique, "Filters on distinct attributes are unique")
+	assert.Equal(t, []commonFilter{filters[0], filters[2]}, shared, "Filters on the same attribute are shared")
+}
@@ -2,12 +2,15 @@ package datalist
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // This is the configuration for a "data list" resource. It represents the schema and operations
@@ -31,6 +34,67 @@ type ResourceConfig struct {
 	// function.
 	GetRecords func(meta interface{}, extra map[string]interface{}) ([]interface{}, error)
 
+	// Return a single page of the records on which the data list resource should operate,
+	// along with the total number of records (negative if unknown). When set, it is used
+	// instead of GetRecords and the records are filtered page by page.
+	GetRecordsPage func(ctx context.Context, meta interface{}, extra map[string]interface{}, offset, limit int) ([]interface{}, int, error)
+
+	// The number of records requested per page by GetRecordsPage. Defaults to DefaultPageSize.
+	PageSize int
+
+	// The number of goroutines filtering each batch of loaded records, a page with
+	// GetRecordsPage or all of the records with GetRecords, in concurrent chunks. The
+	// record predicates, including the ones compiled by ExpressionLanguage, must then be
+	// safe for concurrent use. Records are filtered sequentially when lower than 2.
+	FilterConcurrency int
+
+	// Normalizes the elements of the set attributes of the flattened records, e.g. to
+	// lower case, so that elements differing only by case or whitespace are equal when
+	// the records are filtered, deduplicated by distinct_by and compared. Elements
+	// normalized to the same value are merged.
+	NormalizeSetElement func(attribute string, element interface{}) interface{}
+
+	// Alias tables of the enum match mode, keyed by attribute name. Each table maps
+	// aliases to the canonical values of the attribute, e.g. StatusAliases.
+	EnumAliases map[string]map[string]string
+
+	// Maps record attributes to the API query parameters filtering on them. Filters on
+	// these attributes which use the `in` mode with a single value are pushed down:
+	// they are passed to GetRecords and GetRecordsPage as url.Values in `extra` under
+	// PushdownQueryKey, and are not applied to the loaded records. Filter expressions
+	// are never pushed down.
+	QueryParameters map[string]string
+
+	// Maps record attributes to the API sort keys ordering the records on them. When
+	// `server_sort` selects one of these attributes, its key is passed in the pushdown
+	// query under SortQueryParameter, so that the API returns consistently ordered pages.
+	SortKeys map[string]string
+
+	// The API query parameter carrying the sort key. Defaults to DefaultSortQueryParameter.
+	SortQueryParameter string
+
+	// Returns the provider-supplied variables, keyed by name, which filters can
+	// reference through `variable` instead of listing values. Filters referencing a
+	// variable are rejected when unset.
+	FilterVariables func(meta interface{}) map[string]string
+
+	// Compiles the `expression` attribute, which is only exposed when set. Keeping the
+	// language out of this package leaves its dependency optional, see the jmespath
+	// package.
+	ExpressionLanguage ExpressionLanguage
+
+	// Returns the function reporting the progress of loading the records page by page
+	// with GetRecordsPage, if any.
+	ProgressFunc func(meta interface{}) ProgressFunc
+
+	// Returns the function receiving the durations and record counts of the phases of
+	// each read, if any.
+	StatsFunc func(meta interface{}) StatsFunc
+
+	// Boolean attributes computed from the other attributes of the records, keyed by
+	// name, which are added to the record schema.
+	DerivedAttributes map[string]DerivedAttribute
+
 	// Extra parameters to expose on the datasource alongside `filter` and `sort`.
 	ExtraQuerySchema map[string]*schema.Schema
 }
@@ -46,8 +110,23 @@ func NewResource(config *ResourceConfig) *schema.Resource {
 		log.Panicf("datalist.NewResource: invalid resource configuration: %v", err)
 	}
 
+	// Derived attributes are part of the records like any other attribute.
+	if len(config.DerivedAttributes) > 0 {
+		derivedConfig := *config
+		derivedConfig.RecordSchema = withDerivedAttributes(config.RecordSchema, config.DerivedAttributes)
+		config = &derivedConfig
+	}
+
+	// Records can be fingerprinted unless their schema defines the fingerprint attribute.
+	exposedRecordSchema := config.RecordSchema
+	var fingerprintAttributes []string
+	if _, ok := config.RecordSchema[FingerprintAttribute]; !ok {
+		fingerprintAttributes = computeFilterAttributes(config.RecordSchema)
+		exposedRecordSchema = withFingerprintAttribute(config.RecordSchema)
+	}
+
 	recordSchema := map[string]*schema.Schema{}
-	for attributeName, attributeSchema := range config.RecordSchema {
+	for attributeName, attributeSchema := range exposedRecordSchema {
 		newAttributeSchema := &schema.Schema{}
 		*newAttributeSchema = *attributeSchema
 		newAttributeSchema.Computed = true
@@ -56,12 +135,80 @@ func NewResource(config *ResourceConfig) *schema.Resource {
 		recordSchema[attributeName] = newAttributeSchema
 	}
 
-	filterAttributes := computeFilterAttributes(recordSchema)
+	filterAttributes := computeFilterAttributes(filterRecordSchema(recordSchema))
 	sortAttributes := computeSortAttributes(recordSchema)
 
 	datasourceSchema := map[string]*schema.Schema{
-		"filter": filterSchema(filterAttributes),
-		"sort":   sortSchema(sortAttributes),
+		"filter":                 filterSchema(filterAttributes),
+		"filter_expression":      filterExpressionSchema(),
+		"same_attribute_filters": sameAttributeFiltersSchema(),
+		"sort":                   sortSchema(sortAttributes),
+		"server_sort": {
+			Type:         schema.TypeString,
+			Description:  "The attribute the API is asked to order the records by, so that pages are loaded in a stable order and no record is skipped or duplicated across pages. When the API cannot sort on the attribute, all of the records are loaded and sorted by it instead, so the limit no longer stops loading early. Sorts given by `sort` are applied afterwards",
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice(sortAttributes, false),
+		},
+		"sort_tie_breaker": {
+			Type:        schema.TypeBool,
+			Description: "Whether results equal on all of the sorted attributes are ordered by their id or uuid, in ascending order, so that they are always returned in the same order. Defaults to true",
+			Optional:    true,
+			Default:     true,
+		},
+		"distinct_by": {
+			Type:         schema.TypeString,
+			Description:  "The attribute whose values identify duplicate records. Only the first of the records sharing a value is kept, after sorting",
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice(filterAttributes, false),
+		},
+		"limit": {
+			Type:         schema.TypeInt,
+			Description:  "The maximum number of records to return. Without sort and distinct_by, no more records are loaded once the limit is reached",
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"key_by": {
+			Type:         schema.TypeString,
+			Description:  "The attribute whose values key the results in indexes_by_key. The values must be distinct",
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice(sortAttributes, false),
+		},
+		"indexes_by_key": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Description: fmt.Sprintf("The indexes of the results in %s, keyed by the values of the key_by attribute, e.g. for use with for_each", config.ResultAttributeName),
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
+		"group_by": {
+			Type:         schema.TypeString,
+			Description:  "The attribute whose values group the results counted in counts_by_group. The counts cover all of the results matching the filters, ignoring the limit",
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice(sortAttributes, false),
+		},
+		"counts_by_group": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Description: "The number of results sharing each value of the group_by attribute",
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
+		"export": exportSchema(),
+		"warn_on_empty": {
+			Type:        schema.TypeBool,
+			Description: "If true, a warning listing the filters is emitted when they match no results. Set it to false for lookups whose results are optional",
+			Optional:    true,
+			Default:     true,
+		},
+		"explain": {
+			Type:        schema.TypeBool,
+			Description: "If true, the filters satisfied by each result are listed in explanations, e.g. to find out why a result unexpectedly matches",
+			Optional:    true,
+		},
+		"explanations": explanationsSchema(),
+		"single": {
+			Type:        schema.TypeBool,
+			Description: "If true, the data source fails unless exactly one record matches the filters, and the attributes of that record are exposed at the top level",
+			Optional:    true,
+		},
 		config.ResultAttributeName: {
 			Type:        schema.TypeList,
 			Computed:    true,
@@ -72,48 +219,296 @@ func NewResource(config *ResourceConfig) *schema.Resource {
 		},
 	}
 
+	if fingerprintAttributes != nil {
+		datasourceSchema["fingerprint_attributes"] = fingerprintAttributesSchema(fingerprintAttributes)
+	}
+
+	if config.ExpressionLanguage != nil {
+		datasourceSchema["expression"] = expressionSchema(config.ExpressionLanguage)
+	}
+
 	for attr, value := range config.ExtraQuerySchema {
 		datasourceSchema[attr] = value
 	}
 
+	queryAttributes := queryAttributes(datasourceSchema)
+	datasourceSchema["query_hash"] = queryHashSchema()
+
+	// With `single` set, the attributes of the only matching record are exposed at the
+	// top level, except for those clashing with the data source's own attributes or
+	// its `id`, which is managed by Terraform.
+	var singleAttributes []string
+	for attr, value := range recordSchema {
+		if _, ok := datasourceSchema[attr]; !ok && attr != "id" {
+			singleAttributes = append(singleAttributes, attr)
+			datasourceSchema[attr] = value
+		}
+	}
+
 	return &schema.Resource{
-		ReadContext: dataListResourceRead(config),
+		ReadContext: dataListResourceRead(config, queryAttributes, singleAttributes),
 		Schema:      datasourceSchema,
 	}
 }
 
-func dataListResourceRead(config *ResourceConfig) schema.ReadContextFunc {
+// Returned by the record processing to stop loading records once the limit is reached.
+var errLimitReached = errors.New("limit reached")
+
+// Attributes identifying a record.
+var idAttributes = []string{"id", "uuid"}
+
+func dataListResourceRead(config *ResourceConfig, queryAttributes, singleAttributes []string) schema.ReadContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 		extra := map[string]interface{}{}
 		for attr := range config.ExtraQuerySchema {
 			extra[attr] = d.Get(attr)
 		}
 
-		records, err := config.GetRecords(meta, extra)
-		if err != nil {
-			return diag.Errorf("Unable to load records: %s", err)
+		// The fingerprint is only part of the records when fingerprint_attributes is set.
+		recordSchema := config.RecordSchema
+		var fingerprinted []string
+		if v, ok := d.GetOk("fingerprint_attributes"); ok {
+			for _, attr := range v.([]interface{}) {
+				fingerprinted = append(fingerprinted, attr.(string))
+			}
+			recordSchema = withFingerprintAttribute(recordSchema)
 		}
 
-		flattenedRecords := make([]map[string]interface{}, len(records))
-		for i, record := range records {
-			flattenedRecord, err := config.FlattenRecord(record, meta, extra)
+		filterSchema := filterRecordSchema(recordSchema)
+		expression := filterExpression{op: expressionAnd}
+		query := url.Values{}
+		// The filters applied by the API, which the loaded records all satisfy
+		var pushedFilters []commonFilter
+		if v, ok := d.GetOk("filter"); ok {
+			var variables map[string]string
+			if config.FilterVariables != nil {
+				variables = config.FilterVariables(meta)
+			}
+			rawFilters, err := resolveFilterVariables(v.(*schema.Set).List(), variables)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			filters, err := expandFilters(filterSchema, rawFilters)
 			if err != nil {
 				return diag.FromErr(err)
 			}
-			flattenedRecords[i] = flattenedRecord
+			sameAttributeOr := d.Get("same_attribute_filters").(string) == sameAttributeFiltersOr
+			if len(config.QueryParameters) > 0 {
+				// Filters of an OR group cannot be pushed down, as the API joins
+				// its query parameters with an AND.
+				var grouped []commonFilter
+				if sameAttributeOr {
+					filters, grouped = partitionSameAttributeFilters(filters)
+				}
+				var pushedDown url.Values
+				pushedDown, pushedFilters, filters = pushdownFilters(config.QueryParameters, filters)
+				for param, values := range pushedDown {
+					query[param] = values
+				}
+				filters = append(filters, grouped...)
+			}
+			if sameAttributeOr {
+				expression.children = append(expression.children, compileFilterGroups(filters))
+			} else {
+				expression.children = append(expression.children, compileFilters(filters))
+			}
 		}
+		if v, ok := d.GetOk("filter_expression"); ok {
+			e, err := expandFilterExpression(filterSchema, v.(string))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			expression.children = append(expression.children, e)
+		}
+		if v, ok := d.GetOk("expression"); ok && config.ExpressionLanguage != nil {
+			predicate, err := config.ExpressionLanguage.Compile(v.(string))
+			if err != nil {
+				return diag.Errorf("invalid expression: %s", err)
+			}
+			expression.children = append(expression.children, filterExpression{predicate: predicate})
+		}
+		resolver := newHostnameResolver(ctx)
+		expression.setEnumAliases(config.EnumAliases)
+		expression.setHostnameResolver(resolver)
 
-		if v, ok := d.GetOk("filter"); ok {
-			filters, err := expandFilters(config.RecordSchema, v.(*schema.Set).List())
+		derived, err := compileDerivedAttributes(filterSchema, config.DerivedAttributes)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, d := range derived {
+			d.expression.setEnumAliases(config.EnumAliases)
+			d.expression.setHostnameResolver(resolver)
+		}
+
+		// Records the API cannot sort are sorted once all of them are loaded.
+		var clientSorts []commonSort
+		if v, ok := d.GetOk("server_sort"); ok {
+			if param, key, ok := serverSortQuery(config, v.(string)); ok {
+				query.Set(param, key)
+			} else {
+				clientSorts = append(clientSorts, commonSort{attribute: v.(string), direction: "asc"})
+			}
+		}
+		if len(config.QueryParameters) > 0 || len(config.SortKeys) > 0 {
+			extra[PushdownQueryKey] = query
+		}
+
+		// Records are flattened and filtered as they are loaded, so only the matching
+		// ones are kept in memory. Unless the matching records are sorted, deduplicated
+		// or grouped, or must be a single one, loading stops once the limit is reached.
+		limit := d.Get("limit").(int)
+		_, sorted := d.GetOk("sort")
+		sorted = sorted || len(clientSorts) > 0
+		_, distinct := d.GetOk("distinct_by")
+		_, grouped := d.GetOk("group_by")
+		single := d.Get("single").(bool)
+		stopAtLimit := limit > 0 && !sorted && !distinct && !grouped && !single
+		var stats *readStatsCollector
+		if config.StatsFunc != nil {
+			stats = newReadStatsCollector(config.StatsFunc(meta))
+		}
+		var flattenedRecords []map[string]interface{}
+		processRecords := func(records []interface{}) error {
+			batch := make([]map[string]interface{}, 0, len(records))
+			for _, record := range records {
+				flattenedRecord, err := config.FlattenRecord(record, meta, extra)
+				if err != nil {
+					return err
+				}
+				if config.NormalizeSetElement != nil {
+					normalizeSetElements(recordSchema, flattenedRecord, config.NormalizeSetElement)
+				}
+				setDerivedAttributes(filterSchema, flattenedRecord, derived)
+				if len(fingerprinted) > 0 {
+					fingerprint, err := fingerprintRecord(flattenedRecord, fingerprinted)
+					if err != nil {
+						return err
+					}
+					flattenedRecord[FingerprintAttribute] = fingerprint
+				}
+				batch = append(batch, flattenedRecord)
+			}
+			filterStart := stats.start()
+			matching, err := applyFilterExpressionParallel(ctx, filterSchema, batch, expression, config.FilterConcurrency)
 			if err != nil {
+				return err
+			}
+			stats.filtered(filterStart, len(batch), len(matching))
+			for _, flattenedRecord := range matching {
+				flattenedRecords = append(flattenedRecords, flattenedRecord)
+				if stopAtLimit && len(flattenedRecords) == limit {
+					return errLimitReached
+				}
+			}
+			return nil
+		}
+
+		fetchStart := stats.start()
+		if config.GetRecordsPage != nil {
+			fetch := func(ctx context.Context, offset, limit int) ([]interface{}, int, error) {
+				records, total, err := config.GetRecordsPage(ctx, meta, extra, offset, limit)
+				if err != nil {
+					return nil, 0, fmt.Errorf("Unable to load records: %s", err)
+				}
+				return records, total, nil
+			}
+			if config.ProgressFunc != nil {
+				ctx = WithProgressFunc(ctx, config.ProgressFunc(meta))
+			}
+			if err := StreamPages(ctx, config.PageSize, fetch, processRecords); err != nil && err != errLimitReached {
+				return diag.FromErr(err)
+			}
+		} else {
+			records, err := config.GetRecords(meta, extra)
+			if err != nil {
+				return diag.Errorf("Unable to load records: %s", err)
+			}
+			if err := processRecords(records); err != nil && err != errLimitReached {
 				return diag.FromErr(err)
 			}
-			flattenedRecords = applyFilters(config.RecordSchema, flattenedRecords, filters)
 		}
 
+		stats.fetched(fetchStart)
+
+		sortStart := stats.start()
 		if v, ok := d.GetOk("sort"); ok {
-			sorts := expandSorts(v.([]interface{}))
-			flattenedRecords = applySorts(config.RecordSchema, flattenedRecords, sorts)
+			sorts, err := expandSorts(recordSchema, v.([]interface{}))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			clientSorts = append(sorts, clientSorts...)
+		}
+		if len(clientSorts) > 0 {
+			if d.Get("sort_tie_breaker").(bool) {
+				clientSorts = append(clientSorts, commonSort{direction: "asc", byID: true})
+			}
+			flattenedRecords = applySorts(recordSchema, flattenedRecords, clientSorts)
+		}
+
+		if v, ok := d.GetOk("distinct_by"); ok {
+			flattenedRecords = distinctRecords(flattenedRecords, v.(string))
+		}
+
+		var countsByGroup map[string]interface{}
+		if v, ok := d.GetOk("group_by"); ok {
+			countsByGroup = countRecordsByGroup(flattenedRecords, v.(string))
+		}
+		stats.sorted(sortStart)
+
+		// The records are checked before the limit applies, so that a limit does not
+		// hide the other matching records
+		if single {
+			record, err := expectSingleRecord(flattenedRecords)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			for _, attr := range singleAttributes {
+				if err := d.Set(attr, record[attr]); err != nil {
+					return diag.Errorf("unable to set `%s` attribute: %s", attr, err)
+				}
+			}
+		}
+
+		if limit > 0 && len(flattenedRecords) > limit {
+			flattenedRecords = flattenedRecords[:limit]
+		}
+
+		if v, ok := d.GetOk("export"); ok {
+			e, err := expandExport(recordSchema, v.([]interface{})[0].(map[string]interface{}))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if err := exportRecords(e, flattenedRecords); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		var diags diag.Diagnostics
+		if len(flattenedRecords) == 0 && d.Get("warn_on_empty").(bool) {
+			diags = emptyResultsWarning(d)
+		}
+
+		var indexesByKey map[string]interface{}
+		if v, ok := d.GetOk("key_by"); ok {
+			indexes, err := indexRecordsByKey(flattenedRecords, v.(string))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			indexesByKey = indexes
+		}
+
+		var explanations []interface{}
+		if d.Get("explain").(bool) {
+			explanations = explainRecords(filterSchema, flattenedRecords, expression, pushedFilters)
+		}
+
+		queryArguments := make(map[string]interface{}, len(queryAttributes))
+		for _, attr := range queryAttributes {
+			queryArguments[attr] = d.Get(attr)
+		}
+		hash, err := queryHash(queryArguments)
+		if err != nil {
+			return diag.FromErr(err)
 		}
 
 		d.SetId(resource.UniqueId())
@@ -121,19 +516,45 @@ func dataListResourceRead(config *ResourceConfig) schema.ReadContextFunc {
 		if err := d.Set(config.ResultAttributeName, flattenedRecords); err != nil {
 			return diag.Errorf("unable to set `%s` attribute: %s", config.ResultAttributeName, err)
 		}
+		if err := d.Set("indexes_by_key", indexesByKey); err != nil {
+			return diag.Errorf("unable to set `indexes_by_key` attribute: %s", err)
+		}
+		if err := d.Set("counts_by_group", countsByGroup); err != nil {
+			return diag.Errorf("unable to set `counts_by_group` attribute: %s", err)
+		}
+		if err := d.Set("query_hash", hash); err != nil {
+			return diag.Errorf("unable to set `query_hash` attribute: %s", err)
+		}
+		if err := d.Set("explanations", explanations); err != nil {
+			return diag.Errorf("unable to set `explanations` attribute: %s", err)
+		}
 
-		return nil
+		stats.done(len(flattenedRecords))
+		return diags
+	}
+}
+
+// Returns the record schema extended with the identifier attributes, which can be used
+// in filters even when they are not exposed by the record schema.
+func filterRecordSchema(recordSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	filterSchema := make(map[string]*schema.Schema, len(recordSchema)+len(idAttributes))
+	for attr, schemaForAttr := range recordSchema {
+		filterSchema[attr] = schemaForAttr
 	}
+	for _, attr := range idAttributes {
+		if _, ok := filterSchema[attr]; !ok {
+			filterSchema[attr] = &schema.Schema{Type: schema.TypeString}
+		}
+	}
+	return filterSchema
 }
 
 // Compute the set of filter attributes for the resource.
 func computeFilterAttributes(recordSchema map[string]*schema.Schema) []string {
 	var filterAttributes []string
 
-	for attr, schemaForAttr := range recordSchema {
-		if schemaForAttr.Type != schema.TypeMap {
-			filterAttributes = append(filterAttributes, attr)
-		}
+	for attr := range recordSchema {
+		filterAttributes = append(filterAttributes, attr)
 	}
 
 	return filterAttributes
@@ -165,5 +586,14 @@ func validateResourceConfig(config *ResourceConfig) error {
 		return fmt.Errorf("ResultAttributeName must be specified")
 	}
 
+	// Ensure that exactly one way of loading records is given.
+	if (config.GetRecords == nil) == (config.GetRecordsPage == nil) {
+		return fmt.Errorf("exactly one of GetRecords or GetRecordsPage must be specified")
+	}
+
+	if err := validateDerivedAttributes(config.RecordSchema, config.DerivedAttributes); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,57 @@
+package datalist
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type getRecordsPageFunc = func(ctx context.Context, meta interface{}, extra map[string]interface{}, offset, limit int) ([]interface{}, int, error)
+
+// newTestResourceConfig returns the configuration of a data list resource exposing the
+// given records, which are maps flattened as they are, under the devices attribute.
+// configure, when not nil, sets the fields exercised by a test.
+func newTestResourceConfig(recordSchema map[string]*schema.Schema, records []interface{}, configure func(*ResourceConfig)) *ResourceConfig {
+	config := &ResourceConfig{
+		RecordSchema:        recordSchema,
+		ResultAttributeName: "devices",
+		FlattenRecord: func(record, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+			return record.(map[string]interface{}), nil
+		},
+		GetRecords: func(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+			return records, nil
+		},
+	}
+	if configure != nil {
+		configure(config)
+	}
+	return config
+}
+
+// newTestResource returns the data list resource of newTestResourceConfig.
+func newTestResource(recordSchema map[string]*schema.Schema, records []interface{}, configure func(*ResourceConfig)) *schema.Resource {
+	return NewResource(newTestResourceConfig(recordSchema, records, configure))
+}
+
+// withTestPages makes the configuration load its records with getRecordsPage, pageSize
+// at a time.
+func withTestPages(config *ResourceConfig, pageSize int, getRecordsPage getRecordsPageFunc) {
+	config.GetRecords = nil
+	config.GetRecordsPage = getRecordsPage
+	config.PageSize = pageSize
+}
+
+// newTestNumbersResource returns a data list resource exposing the numbers loaded by
+// getRecordsPage, pageSize at a time, under the numbers attribute.
+func newTestNumbersResource(pageSize int, getRecordsPage getRecordsPageFunc, configure func(*ResourceConfig)) *schema.Resource {
+	return newTestResource(map[string]*schema.Schema{"number": {Type: schema.TypeInt}}, nil, func(config *ResourceConfig) {
+		config.ResultAttributeName = "numbers"
+		config.FlattenRecord = func(record, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+			return map[string]interface{}{"number": record.(int)}, nil
+		}
+		withTestPages(config, pageSize, getRecordsPage)
+		if configure != nil {
+			configure(config)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package datalist
+
+// UnionRecords returns the records of primary followed by the records of secondary
+// whose identifiers are not in primary, both in their original order. Records are
+// identified as in DiffRecords; those without an identifier are all kept.
+func UnionRecords(primary, secondary []map[string]interface{}) []map[string]interface{} {
+	seen := recordIDs(primary)
+	union := append(primary[:0:0], primary...)
+	for _, record := range secondary {
+		id := recordID(record)
+		if id != "" {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+		union = append(union, record)
+	}
+	return union
+}
+
+// IntersectRecords returns the records of primary whose identifiers are also in
+// secondary, in the order of primary. Records without an identifier are dropped.
+func IntersectRecords(primary, secondary []map[string]interface{}) []map[string]interface{} {
+	other := recordIDs(secondary)
+	intersection := primary[:0:0]
+	for _, record := range primary {
+		if id := recordID(record); id != "" && other[id] {
+			intersection = append(intersection, record)
+		}
+	}
+	return intersection
+}
+
+// SubtractRecords returns the records of primary whose identifiers are not in
+// secondary, in the order of primary, e.g. ports in a metro minus ports under
+// maintenance. Records without an identifier are kept.
+func SubtractRecords(primary, secondary []map[string]interface{}) []map[string]interface{} {
+	other := recordIDs(secondary)
+	difference := primary[:0:0]
+	for _, record := range primary {
+		if id := recordID(record); id == "" || !other[id] {
+			difference = append(difference, record)
+		}
+	}
+	return difference
+}
+
+func recordIDs(records []map[string]interface{}) map[string]bool {
+	ids := make(map[string]bool, len(records))
+	for _, record := range records {
+		if id := recordID(record); id != "" {
+			ids[id] = true
+		}
+	}
+	return ids
+}
@@ -0,0 +1,69 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setOperationsTestRecords() ([]map[string]interface{}, []map[string]interface{}) {
+	inMetro := []map[string]interface{}{
+		{"id": "port-3", "name": "three"},
+		{"id": "port-1", "name": "one"},
+		{"name": "no-id"},
+		{"id": "port-2", "name": "two"},
+	}
+	inMaintenance := []map[string]interface{}{
+		{"uuid": "port-4", "name": "four"},
+		{"id": "port-1", "name": "one-updated"},
+		{"name": "other-no-id"},
+		{"id": "port-3", "name": "three"},
+	}
+	return inMetro, inMaintenance
+}
+
+func recordNames(records []map[string]interface{}) []string {
+	names := []string{}
+	for _, record := range records {
+		names = append(names, record["name"].(string))
+	}
+	return names
+}
+
+func TestUnionRecords(t *testing.T) {
+	// given
+	primary, secondary := setOperationsTestRecords()
+	// when
+	union := UnionRecords(primary, secondary)
+	// then
+	assert.Equal(t, []string{"three", "one", "no-id", "two", "four", "other-no-id"}, recordNames(union), "Records of primary are kept in order, followed by new records of secondary")
+	assert.Len(t, primary, 4, "Primary records are not modified")
+}
+
+func TestIntersectRecords(t *testing.T) {
+	// given
+	primary, secondary := setOperationsTestRecords()
+	// when
+	intersection := IntersectRecords(primary, secondary)
+	// then
+	assert.Equal(t, []string{"three", "one"}, recordNames(intersection), "Records of primary present in secondary are kept in order")
+}
+
+func TestSubtractRecords(t *testing.T) {
+	// given
+	primary, secondary := setOperationsTestRecords()
+	// when
+	difference := SubtractRecords(primary, secondary)
+	// then
+	assert.Equal(t, []string{"no-id", "two"}, recordNames(difference), "Records of primary missing from secondary are kept in order")
+}
+
+func TestSetOperations_empty(t *testing.T) {
+	// given
+	primary, _ := setOperationsTestRecords()
+	// then
+	assert.Equal(t, recordNames(primary), recordNames(UnionRecords(primary, nil)))
+	assert.Empty(t, IntersectRecords(primary, nil))
+	assert.Equal(t, recordNames(primary), recordNames(SubtractRecords(primary, nil)))
+	assert.Equal(t, []string{"three", "one", "no-id", "two"}, recordNames(UnionRecords(nil, primary)))
+}
@@ -0,0 +1,46 @@
+package datalist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The maximum number of matching record identifiers listed in the error returned when
+// the `single` mode does not find exactly one record.
+const singleMaxListedIDs = 5
+
+// Returns the only record in the given list, or an error describing how many records
+// matched along with a few of their identifiers.
+func expectSingleRecord(records []map[string]interface{}) (map[string]interface{}, error) {
+	switch len(records) {
+	case 1:
+		return records[0], nil
+	case 0:
+		return nil, fmt.Errorf("expected a single record to match, got none")
+	}
+
+	var ids []string
+	for _, record := range records {
+		if len(ids) == singleMaxListedIDs {
+			ids = append(ids, "...")
+			break
+		}
+		if id := recordID(record); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("expected a single record to match, got %d", len(records))
+	}
+	return nil, fmt.Errorf("expected a single record to match, got %d: %s", len(records), strings.Join(ids, ", "))
+}
+
+// Returns the value of the first identifier attribute set on the record.
+func recordID(record map[string]interface{}) string {
+	for _, attr := range idAttributes {
+		if v, ok := record[attr]; ok && v != nil && fmt.Sprint(v) != "" {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSingleTestResource(records []interface{}) *schema.Resource {
+	return newTestResource(map[string]*schema.Schema{
+		"uuid": {Type: schema.TypeString},
+		"name": {Type: schema.TypeString},
+	}, records, nil)
+}
+
+func TestNewResource_single(t *testing.T) {
+	records := []interface{}{
+		map[string]interface{}{"uuid": "a", "name": "router"},
+		map[string]interface{}{"uuid": "b", "name": "firewall"},
+		map[string]interface{}{"uuid": "c", "name": "firewall"},
+	}
+	testCases := []struct {
+		name          string
+		value         string
+		expectedError string
+		expectedUUID  string
+	}{
+		{"NoMatch", "switch", "expected a single record to match, got none", ""},
+		{"SingleMatch", "router", "", "a"},
+		{"MultipleMatches", "firewall", "expected a single record to match, got 2: b, c", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resource := newSingleTestResource(records)
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+				"single": true,
+				"filter": []interface{}{
+					map[string]interface{}{
+						"attribute": "name",
+						"values":    []interface{}{testCase.value},
+					},
+				},
+			})
+
+			diags := resource.ReadContext(context.Background(), d, nil)
+
+			if testCase.expectedError != "" {
+				assert.True(t, diags.HasError(), "read returns an error")
+				assert.Equal(t, testCase.expectedError, diags[0].Summary)
+				return
+			}
+			assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+			assert.Equal(t, testCase.expectedUUID, d.Get("uuid"))
+			assert.Equal(t, testCase.value, d.Get("name"))
+			assert.Len(t, d.Get("devices").([]interface{}), 1)
+		})
+	}
+}
+
+func TestNewResource_singleWithLimit(t *testing.T) {
+	// given
+	resource := newSingleTestResource([]interface{}{
+		map[string]interface{}{"uuid": "b", "name": "firewall"},
+		map[string]interface{}{"uuid": "c", "name": "firewall"},
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"single": true,
+		"limit":  1,
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "name", "values": []interface{}{"firewall"}},
+		},
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.True(t, diags.HasError(), "The limit does not hide the other matching records")
+	assert.Equal(t, "expected a single record to match, got 2: b, c", diags[0].Summary)
+}
+
+func TestExpectSingleRecord_listedIDs(t *testing.T) {
+	var records []map[string]interface{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		records = append(records, map[string]interface{}{"id": id})
+	}
+
+	_, err := expectSingleRecord(records)
+
+	assert.EqualError(t, err, "expected a single record to match, got 7: a, b, c, d, e, ...")
+}
+
+func TestNewResource_singleSchema(t *testing.T) {
+	resource := newSingleTestResource(nil)
+
+	assert.NoError(t, resource.InternalValidate(nil, false))
+	assert.True(t, resource.Schema["name"].Computed)
+}
@@ -1,6 +1,7 @@
 package datalist
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 
@@ -13,6 +14,12 @@ var sortAttributes = []string{"asc", "desc"}
 type commonSort struct {
 	attribute string
 	direction string
+	// Sorts RFC3339 timestamps by their age instead of their value
+	byAge bool
+	// Sorts RFC3339 timestamps in chronological order instead of by their value
+	byTime bool
+	// Sorts on the first identifier attribute set on both records, ignoring attribute
+	byID bool
 }
 
 func sortSchema(allowedAttributes []string) *schema.Schema {
@@ -32,6 +39,13 @@ func sortSchema(allowedAttributes []string) *schema.Schema {
 					Optional:     true,
 					ValidateFunc: validation.StringInSlice(sortAttributes, false),
 				},
+				"by": {
+					Type:         schema.TypeString,
+					Description:  "The value sorted on. One of: value (default), age, time. The age, the time elapsed since an RFC3339 timestamp, sorts string attributes from the newest to the oldest timestamp in ascending order. The time sorts them from the oldest to the newest timestamp, comparing timestamps with different offsets by the instant they refer to, e.g. 2024-01-01T00:00:00-05:00 sorts after 2024-01-01T04:00:00Z. Invalid timestamps are considered the oldest",
+					Optional:     true,
+					Default:      "value",
+					ValidateFunc: validation.StringInSlice([]string{"value", "age", "time"}, false),
+				},
 			},
 		},
 		Optional:    true,
@@ -39,7 +53,7 @@ func sortSchema(allowedAttributes []string) *schema.Schema {
 	}
 }
 
-func expandSorts(rawSorts []interface{}) []commonSort {
+func expandSorts(recordSchema map[string]*schema.Schema, rawSorts []interface{}) ([]commonSort, error) {
 	expandedSorts := make([]commonSort, len(rawSorts))
 	for i, rawSort := range rawSorts {
 		f := rawSort.(map[string]interface{})
@@ -48,10 +62,17 @@ func expandSorts(rawSorts []interface{}) []commonSort {
 			attribute: f["attribute"].(string),
 			direction: f["direction"].(string),
 		}
+		if by, _ := f["by"].(string); by == "age" || by == "time" {
+			if s, ok := recordSchema[expandedSort.attribute]; !ok || s.Type != schema.TypeString {
+				return nil, fmt.Errorf("sort by %s is not supported by field '%s', which is not a string", by, expandedSort.attribute)
+			}
+			expandedSort.byAge = by == "age"
+			expandedSort.byTime = by == "time"
+		}
 
 		expandedSorts[i] = expandedSort
 	}
-	return expandedSorts
+	return expandedSorts, nil
 }
 
 func applySorts(recordSchema map[string]*schema.Schema, records []map[string]interface{}, sorts []commonSort) []map[string]interface{} {
@@ -68,7 +89,16 @@ func applySorts(recordSchema map[string]*schema.Schema, records []map[string]int
 
 			value1 := records[i]
 			value2 := records[j]
-			cmp := compareValues(recordSchema[s.attribute], value1[s.attribute], value2[s.attribute])
+			var cmp int
+			if s.byID {
+				cmp = compareIDs(recordSchema, value1, value2)
+			} else if s.byAge {
+				cmp = compareAges(value1[s.attribute], value2[s.attribute])
+			} else if s.byTime {
+				cmp = compareTimestamps(value1[s.attribute], value2[s.attribute])
+			} else {
+				cmp = compareValues(recordSchema[s.attribute], value1[s.attribute], value2[s.attribute])
+			}
 			if cmp != 0 {
 				return cmp < 0
 			}
@@ -79,3 +109,25 @@ func applySorts(recordSchema map[string]*schema.Schema, records []map[string]int
 
 	return records
 }
+
+// Compares the first identifier attribute set on both records, as strings when it is
+// not part of the record schema. Records without a common identifier are equal.
+func compareIDs(recordSchema map[string]*schema.Schema, record1, record2 map[string]interface{}) int {
+	for _, attr := range idAttributes {
+		id1, id2 := record1[attr], record2[attr]
+		if id1 == nil || id2 == nil {
+			continue
+		}
+		if s, ok := recordSchema[attr]; ok && isPrimitiveType(s.Type) {
+			return compareValues(s, id1, id2)
+		}
+		return strings.Compare(fmt.Sprint(id1), fmt.Sprint(id2))
+	}
+	return 0
+}
+
+// Compares the ages of two RFC3339 timestamps, which are the opposite of their
+// chronological order. Invalid timestamps are considered older than any valid one.
+func compareAges(value1, value2 interface{}) int {
+	return compareTimestamps(value2, value1)
+}
@@ -1,9 +1,11 @@
 package datalist
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 )
 
 func sizesTestDataForSorts() []map[string]interface{} {
@@ -59,7 +61,14 @@ func TestExpandSorts(t *testing.T) {
 		},
 	}
 
-	expandedSorts := expandSorts(rawSorts)
+	expandedSorts, err := expandSorts(map[string]*schema.Schema{
+		"fieldA": {Type: schema.TypeString},
+		"fieldB": {Type: schema.TypeInt},
+	}, rawSorts)
+
+	if err != nil {
+		t.Fatalf("expandSorts returned error: %s", err)
+	}
 
 	if len(rawSorts) != len(expandedSorts) {
 		t.Fatalf("incorrect expected length of expanded sorts")
@@ -92,7 +101,7 @@ func TestApplySorts(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			// Test ascending order
-			sizes := applySorts(sizesTestSchema(), sizesTestDataForSorts(), []commonSort{{testCase.attribute, "asc"}})
+			sizes := applySorts(sizesTestSchema(), sizesTestDataForSorts(), []commonSort{{attribute: testCase.attribute, direction: "asc"}})
 			if len(sizes) != len(testCase.expectedAsc) {
 				t.Fatalf("Expecting %d size results, found %d size results instead", len(testCase.expectedAsc), len(sizes))
 			}
@@ -103,7 +112,7 @@ func TestApplySorts(t *testing.T) {
 			}
 
 			// Test descending order
-			sizes = applySorts(sizesTestSchema(), sizesTestDataForSorts(), []commonSort{{testCase.attribute, "desc"}})
+			sizes = applySorts(sizesTestSchema(), sizesTestDataForSorts(), []commonSort{{attribute: testCase.attribute, direction: "desc"}})
 			if len(sizes) != len(testCase.expectedAsc) {
 				t.Fatalf("Expecting %d size results, found %d size results instead", len(testCase.expectedAsc), len(sizes))
 			}
@@ -155,8 +164,8 @@ func TestApplySortsMultiple(t *testing.T) {
 
 	// Test ascending order
 	sizes := applySorts(sizesTestSchema(), testData, []commonSort{
-		{"memory", "desc"}, // Sort by memory descendingly first
-		{"disk", "asc"},    // Then for sizes with same memory, sort by disk ascendingly
+		{attribute: "memory", direction: "desc"}, // Sort by memory descendingly first
+		{attribute: "disk", direction: "asc"},    // Then for sizes with same memory, sort by disk ascendingly
 	})
 
 	if len(sizes) != 3 {
@@ -172,3 +181,40 @@ func TestApplySortsMultiple(t *testing.T) {
 		t.Fatalf("Expecting sizes to be sorted by memory in descending order, then by disk in ascending order")
 	}
 }
+
+func TestNewResource_sortTieBreaker(t *testing.T) {
+	// given
+	resource := newTestResource(map[string]*schema.Schema{
+		"uuid":       {Type: schema.TypeString},
+		"name":       {Type: schema.TypeString},
+		"metro_code": {Type: schema.TypeString},
+	}, []interface{}{
+		map[string]interface{}{"uuid": "c", "name": "dev-c", "metro_code": "SV"},
+		map[string]interface{}{"uuid": "d", "name": "dev-d", "metro_code": "DC"},
+		map[string]interface{}{"uuid": "a", "name": "dev-a", "metro_code": "SV"},
+		map[string]interface{}{"uuid": "b", "name": "dev-b", "metro_code": "SV"},
+	}, nil)
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"sort": []interface{}{
+			map[string]interface{}{"attribute": "metro_code", "direction": "desc"},
+		},
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	var names []string
+	for _, device := range d.Get("devices").([]interface{}) {
+		names = append(names, device.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"dev-a", "dev-b", "dev-c", "dev-d"}, names, "Records tying on metro_code are ordered by uuid")
+}
+
+func TestCompareIDs(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{"id": {Type: schema.TypeInt}}
+
+	assert.Equal(t, -1, compareIDs(recordSchema, map[string]interface{}{"id": 9}, map[string]interface{}{"id": 10}), "Identifiers in the record schema are compared by type")
+	assert.Equal(t, 1, compareIDs(nil, map[string]interface{}{"uuid": "b"}, map[string]interface{}{"uuid": "a"}))
+	assert.Equal(t, -1, compareIDs(nil, map[string]interface{}{"id": nil, "uuid": "a"}, map[string]interface{}{"id": "z", "uuid": "b"}), "Identifiers unset on either record are skipped")
+	assert.Equal(t, 0, compareIDs(nil, map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}))
+}
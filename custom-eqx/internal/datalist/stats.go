@@ -0,0 +1,82 @@
+package datalist
+
+import "time"
+
+// ReadStats are the durations and record counts of the phases of a data list read,
+// e.g. to log where the time of reads over many records goes.
+type ReadStats struct {
+	// Time spent loading and flattening the records, excluding their filtering
+	FetchDuration time.Duration
+	// Time spent filtering the loaded records
+	FilterDuration time.Duration
+	// Time spent sorting, deduplicating and grouping the matching records
+	SortDuration time.Duration
+	// Number of records loaded
+	Fetched int
+	// Number of loaded records matching the filters
+	Matched int
+	// Number of records returned, once deduplicated and limited
+	Returned int
+}
+
+// StatsFunc is called with the ReadStats of each successful data list read.
+type StatsFunc func(ReadStats)
+
+// readStatsCollector measures the phases of a read. A nil collector measures nothing,
+// without even reading the clock, so that reads pay nothing for the stats unless they
+// are reported.
+type readStatsCollector struct {
+	stats  ReadStats
+	report StatsFunc
+}
+
+func newReadStatsCollector(report StatsFunc) *readStatsCollector {
+	if report == nil {
+		return nil
+	}
+	return &readStatsCollector{report: report}
+}
+
+// Returns the start time of a phase.
+func (c *readStatsCollector) start() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// Records the filtering of a batch of loaded records, which started at the given time.
+func (c *readStatsCollector) filtered(start time.Time, fetched, matched int) {
+	if c == nil {
+		return
+	}
+	c.stats.FilterDuration += time.Since(start)
+	c.stats.Fetched += fetched
+	c.stats.Matched += matched
+}
+
+// Records the end of the loading of the records, which started at the given time and
+// includes their filtering.
+func (c *readStatsCollector) fetched(start time.Time) {
+	if c == nil {
+		return
+	}
+	c.stats.FetchDuration = time.Since(start) - c.stats.FilterDuration
+}
+
+// Records the end of the sorting of the records, which started at the given time.
+func (c *readStatsCollector) sorted(start time.Time) {
+	if c == nil {
+		return
+	}
+	c.stats.SortDuration = time.Since(start)
+}
+
+// Reports the stats of the read, which returned the given number of records.
+func (c *readStatsCollector) done(returned int) {
+	if c == nil {
+		return
+	}
+	c.stats.Returned = returned
+	c.report(c.stats)
+}
@@ -0,0 +1,55 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResource_stats(t *testing.T) {
+	// given
+	var reports []ReadStats
+	resource := newTestNumbersResource(3, newTestPager(10, true).getRecordsPage, func(config *ResourceConfig) {
+		config.StatsFunc = func(meta interface{}) StatsFunc {
+			return func(stats ReadStats) {
+				reports = append(reports, stats)
+			}
+		}
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{"attribute": "number", "values": []interface{}{"6"}, "match_by": "greater_than_or_equal"},
+		},
+		"sort":  []interface{}{map[string]interface{}{"attribute": "number", "direction": "desc"}},
+		"limit": 3,
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, nil)
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	if assert.Len(t, reports, 1, "Stats are reported once per read") {
+		stats := reports[0]
+		assert.Equal(t, 10, stats.Fetched, "All of the loaded records are counted")
+		assert.Equal(t, 4, stats.Matched, "The records matching the filters are counted")
+		assert.Equal(t, 3, stats.Returned, "The limited records are counted")
+		assert.GreaterOrEqual(t, int64(stats.FetchDuration), int64(0), "Fetch duration is not negative")
+		assert.GreaterOrEqual(t, int64(stats.FilterDuration), int64(0), "Filter duration is not negative")
+		assert.GreaterOrEqual(t, int64(stats.SortDuration), int64(0), "Sort duration is not negative")
+	}
+}
+
+func TestReadStatsCollector_disabled(t *testing.T) {
+	// given
+	collector := newReadStatsCollector(nil)
+	// when
+	start := collector.start()
+	collector.filtered(start, 1, 1)
+	collector.fetched(start)
+	collector.sorted(start)
+	collector.done(1)
+	// then
+	assert.Nil(t, collector, "No collector is created without a StatsFunc")
+	assert.True(t, start.IsZero(), "The clock is not read without a StatsFunc")
+}
@@ -0,0 +1,30 @@
+package datalist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// strBetweenFilterValue is the filter value of the str_between match mode, matching
+// strings between the bounds, included, in lexicographic byte order.
+type strBetweenFilterValue struct {
+	low  string
+	high string
+}
+
+// Collapses the expanded filter values of the str_between match mode, which are the
+// low and high bounds, into a single value.
+func newStrBetweenFilterValue(values []interface{}) (strBetweenFilterValue, error) {
+	if len(values) != 2 {
+		return strBetweenFilterValue{}, fmt.Errorf("str_between takes two values, the low and high bounds, got %d", len(values))
+	}
+	v := strBetweenFilterValue{low: values[0].(string), high: values[1].(string)}
+	if strings.Compare(v.low, v.high) > 0 {
+		return strBetweenFilterValue{}, fmt.Errorf("the low bound %q of str_between is greater than the high bound %q", v.low, v.high)
+	}
+	return v, nil
+}
+
+func (v strBetweenFilterValue) matches(value string) bool {
+	return strings.Compare(v.low, value) <= 0 && strings.Compare(value, v.high) <= 0
+}
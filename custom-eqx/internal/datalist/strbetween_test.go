@@ -0,0 +1,61 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_strBetween(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+	}
+	var records []map[string]interface{}
+	for _, name := range []string{"apple", "m", "mango", "Melon", "n", "nectarine", "zucchini"} {
+		records = append(records, map[string]interface{}{"name": name})
+	}
+	testCases := []struct {
+		name          string
+		transform     []interface{}
+		expectedNames []string
+	}{
+		{"CaseSensitive", nil, []string{"m", "mango", "n"}},
+		{"CaseInsensitive", []interface{}{"lower"}, []string{"m", "mango", "Melon", "n"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{"attribute": "name", "values": []interface{}{"m", "n"}, "match_by": "str_between", "transform": testCase.transform},
+			})
+			// when
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedNames, names, "Names between m and n, bounds included")
+		})
+	}
+}
+
+func TestExpandFilters_strBetweenInvalid(t *testing.T) {
+	// given
+	recordSchema := map[string]*schema.Schema{
+		"name":    {Type: schema.TypeString},
+		"vlan_id": {Type: schema.TypeInt},
+	}
+	for _, rawFilter := range []map[string]interface{}{
+		{"attribute": "name", "values": []interface{}{"m"}, "match_by": "str_between"},
+		{"attribute": "name", "values": []interface{}{"a", "m", "z"}, "match_by": "str_between"},
+		{"attribute": "name", "values": []interface{}{"n", "m"}, "match_by": "str_between"},
+		{"attribute": "vlan_id", "values": []interface{}{"1", "2"}, "match_by": "str_between"},
+	} {
+		// when
+		_, err := expandFilters(recordSchema, []interface{}{rawFilter})
+		// then
+		assert.Error(t, err, "Filter %v is rejected", rawFilter)
+	}
+}
@@ -0,0 +1,48 @@
+package datalist
+
+import (
+	"fmt"
+	"time"
+)
+
+// Parses an RFC3339 timestamp into UTC, so that timestamps with different offsets,
+// e.g. 2024-01-01T00:00:00-05:00 and 2024-01-01T04:00:00Z, compare by the instant
+// they refer to rather than by their text.
+func parseTimestamp(value string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.UTC(), true
+}
+
+// Parses a filter value of the before and after match modes.
+func parseTimestampFilterValue(filterValue string) (time.Time, error) {
+	t, ok := parseTimestamp(filterValue)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unable to parse value as RFC3339 timestamp: %s", filterValue)
+	}
+	return t, nil
+}
+
+// Compares two RFC3339 timestamps in chronological order. Invalid timestamps are
+// considered older than any valid one.
+func compareTimestamps(value1, value2 interface{}) int {
+	t1, ok1 := parseTimestamp(fmt.Sprint(value1))
+	t2, ok2 := parseTimestamp(fmt.Sprint(value2))
+	switch {
+	case !ok1 && !ok2:
+		return 0
+	case !ok1:
+		return -1
+	case !ok2:
+		return 1
+	}
+	switch {
+	case t1.Before(t2):
+		return -1
+	case t1.After(t2):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+var timestampTestSchema = map[string]*schema.Schema{
+	"name":         {Type: schema.TypeString},
+	"created_date": {Type: schema.TypeString},
+}
+
+func timestampTestRecords() []map[string]interface{} {
+	// In chronological order: dev-2, dev-1, dev-4, while their text sorts dev-1 first
+	return []map[string]interface{}{
+		{"name": "dev-1", "created_date": "2024-01-01T00:00:00-05:00"},
+		{"name": "dev-2", "created_date": "2024-01-01T04:00:00Z"},
+		{"name": "dev-3", "created_date": "invalid"},
+		{"name": "dev-4", "created_date": "2024-01-01T07:30:00+02:00"},
+	}
+}
+
+func TestApplyFilters_timestamps(t *testing.T) {
+	testCases := []struct {
+		name         string
+		matchBy      string
+		value        string
+		expectations []string
+	}{
+		{"AfterUTC", "after", "2024-01-01T04:00:00Z", []string{"dev-1", "dev-4"}},
+		{"BeforeOffset", "before", "2024-01-01T00:00:00-05:00", []string{"dev-2"}},
+		{"BeforeEquivalentInstant", "before", "2024-01-01T05:00:00Z", []string{"dev-2"}},
+		{"AfterOffset", "after", "2024-01-01T09:30:00+05:00", []string{"dev-1", "dev-4"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			filters, err := expandFilters(timestampTestSchema, []interface{}{
+				map[string]interface{}{
+					"attribute": "created_date",
+					"values":    []interface{}{testCase.value},
+					"match_by":  testCase.matchBy,
+				},
+			})
+			assert.NoError(t, err)
+			// when
+			var names []string
+			for _, record := range applyFilters(timestampTestSchema, timestampTestRecords(), filters) {
+				names = append(names, record["name"].(string))
+			}
+			// then
+			assert.Equal(t, testCase.expectations, names, "Timestamps are compared by the instant they refer to")
+		})
+	}
+}
+
+func TestExpandFilters_invalidTimestamp(t *testing.T) {
+	// when
+	_, err := expandFilters(timestampTestSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "created_date",
+			"values":    []interface{}{"yesterday"},
+			"match_by":  "after",
+		},
+	})
+	// then
+	assert.Error(t, err, "Filter values must be RFC3339 timestamps")
+}
+
+func TestApplySorts_timestamps(t *testing.T) {
+	testCases := []struct {
+		name         string
+		by           string
+		direction    string
+		expectations []string
+	}{
+		{"TimeAsc", "time", "asc", []string{"dev-3", "dev-2", "dev-1", "dev-4"}},
+		{"TimeDesc", "time", "desc", []string{"dev-4", "dev-1", "dev-2", "dev-3"}},
+		{"Age", "age", "asc", []string{"dev-4", "dev-1", "dev-2", "dev-3"}},
+		{"Value", "value", "asc", []string{"dev-1", "dev-2", "dev-4", "dev-3"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			sorts, err := expandSorts(timestampTestSchema, []interface{}{
+				map[string]interface{}{"attribute": "created_date", "direction": testCase.direction, "by": testCase.by},
+			})
+			assert.NoError(t, err)
+			// when
+			var names []string
+			for _, record := range applySorts(timestampTestSchema, timestampTestRecords(), sorts) {
+				names = append(names, record["name"].(string))
+			}
+			// then
+			assert.Equal(t, testCase.expectations, names)
+		})
+	}
+}
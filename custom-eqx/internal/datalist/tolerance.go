@@ -0,0 +1,46 @@
+package datalist
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The absolute tolerance of float comparisons, unless a filter sets its own.
+const defaultFloatTolerance = 0.000001
+
+// tolerantFloatValue is the filter value of a float filter setting the tolerance of
+// its comparisons.
+type tolerantFloatValue struct {
+	value     float64
+	tolerance float64
+}
+
+// Wraps the expanded filter values with the tolerance, which is only supported by
+// float attributes.
+func toleranceFilterValues(attr string, s *schema.Schema, values []interface{}, tolerance float64) ([]interface{}, error) {
+	fieldType := s.Type
+	if elem, ok := s.Elem.(*schema.Schema); ok && !isPrimitiveType(fieldType) && fieldType != schema.TypeMap {
+		fieldType = elem.Type
+	}
+	if fieldType != schema.TypeFloat {
+		return nil, fmt.Errorf("tolerance is not supported by field '%s' of type %s", attr, fieldType)
+	}
+	if tolerance < 0 {
+		return nil, fmt.Errorf("tolerance of field '%s' cannot be negative, got: %v", attr, tolerance)
+	}
+	wrapped := make([]interface{}, len(values))
+	for i, value := range values {
+		wrapped[i] = tolerantFloatValue{value: value.(float64), tolerance: tolerance}
+	}
+	return wrapped, nil
+}
+
+// Reports whether the filter sets the tolerance of its comparisons.
+func hasTolerance(f commonFilter) bool {
+	if len(f.values) == 0 {
+		return false
+	}
+	_, ok := f.values[0].(tolerantFloatValue)
+	return ok
+}
@@ -0,0 +1,80 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_tolerance(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name":      {Type: schema.TypeString},
+		"latency":   {Type: schema.TypeFloat},
+		"latencies": {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeFloat}},
+	}
+	records := []map[string]interface{}{
+		{"name": "link-1", "latency": 1.4999999, "latencies": []interface{}{1.4999999}},
+		{"name": "link-2", "latency": 1.49, "latencies": []interface{}{2.0, 1.51}},
+		{"name": "link-3", "latency": 1.6, "latencies": []interface{}{1.6}},
+	}
+	testCases := []struct {
+		name         string
+		filter       map[string]interface{}
+		expectations []string
+	}{
+		{
+			"DefaultTolerance",
+			map[string]interface{}{"attribute": "latency", "values": []interface{}{"1.5"}},
+			[]string{"link-1"},
+		},
+		{
+			"CustomTolerance",
+			map[string]interface{}{"attribute": "latency", "values": []interface{}{"1.5"}, "tolerance": 0.02},
+			[]string{"link-1", "link-2"},
+		},
+		{
+			"CustomToleranceOfComparison",
+			map[string]interface{}{"attribute": "latency", "values": []interface{}{"1.5"}, "tolerance": 0.2, "match_by": "greater_than_or_equal"},
+			[]string{"link-1", "link-2", "link-3"},
+		},
+		{
+			"CustomToleranceOfList",
+			map[string]interface{}{"attribute": "latencies", "values": []interface{}{"1.5"}, "tolerance": 0.02},
+			[]string{"link-1", "link-2"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{testCase.filter})
+			assert.NoError(t, err)
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectations, names)
+		})
+	}
+
+	_, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{"attribute": "name", "values": []interface{}{"link-1"}, "tolerance": 0.1},
+	})
+	assert.EqualError(t, err, "tolerance is not supported by field 'name' of type TypeString")
+	_, err = expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{"attribute": "latency", "values": []interface{}{"1.5"}, "tolerance": -0.1},
+	})
+	assert.EqualError(t, err, "tolerance of field 'latency' cannot be negative, got: -0.1")
+}
+
+func TestFilterExpression_tolerance(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"latency": {Type: schema.TypeFloat},
+	}
+
+	expression, err := expandFilterExpression(recordSchema, `{"attribute": "latency", "values": [1.5], "tolerance": 0.02}`)
+
+	assert.NoError(t, err)
+	assert.True(t, expression.matches(recordSchema, map[string]interface{}{"latency": 1.49}))
+	assert.False(t, expression.matches(recordSchema, map[string]interface{}{"latency": 1.45}))
+}
@@ -0,0 +1,93 @@
+package datalist
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The transforms which can be applied to string values before they are compared.
+// Transforms report whether they could be applied to the value, as decoding may fail.
+var stringTransforms = map[string]func(string) (string, bool){
+	"lower":        infallibleTransform(strings.ToLower),
+	"upper":        infallibleTransform(strings.ToUpper),
+	"trim":         infallibleTransform(strings.TrimSpace),
+	"base64decode": base64Decode,
+	"hexdecode":    hexDecode,
+}
+
+func stringTransformNames() []string {
+	return []string{"lower", "upper", "trim", "base64decode", "hexdecode"}
+}
+
+func infallibleTransform(transform func(string) string) func(string) (string, bool) {
+	return func(value string) (string, bool) {
+		return transform(value), true
+	}
+}
+
+// Decodes standard base64, with or without padding.
+func base64Decode(value string) (string, bool) {
+	encoding := base64.StdEncoding
+	if !strings.HasSuffix(value, "=") && len(value)%4 != 0 {
+		encoding = base64.RawStdEncoding
+	}
+	decoded, err := encoding.DecodeString(value)
+	return string(decoded), err == nil
+}
+
+func hexDecode(value string) (string, bool) {
+	decoded, err := hex.DecodeString(value)
+	return string(decoded), err == nil
+}
+
+// transformedFilterValue wraps the filter value of a filter which transforms the
+// record values, in order, before comparing them with the value.
+type transformedFilterValue struct {
+	transforms []string
+	value      interface{}
+}
+
+// Applies the transforms to the value, reporting whether all of them could be applied.
+// Values which cannot be transformed do not match the filter.
+func (v transformedFilterValue) apply(value string) (string, bool) {
+	for _, name := range v.transforms {
+		var ok bool
+		if value, ok = stringTransforms[name](value); !ok {
+			return "", false
+		}
+	}
+	return value, true
+}
+
+// Wraps the expanded filter values with the transforms, which are only supported by
+// string attributes.
+func transformFilterValues(attr string, s *schema.Schema, values []interface{}, transforms []string) ([]interface{}, error) {
+	if len(transforms) == 0 {
+		return values, nil
+	}
+	fieldType := s.Type
+	if elem, ok := s.Elem.(*schema.Schema); ok && !isPrimitiveType(fieldType) && fieldType != schema.TypeMap {
+		fieldType = elem.Type
+	}
+	if fieldType != schema.TypeString {
+		return nil, fmt.Errorf("transform is not supported by field '%s' of type %s", attr, fieldType)
+	}
+	transformed := make([]interface{}, len(values))
+	for i, value := range values {
+		transformed[i] = transformedFilterValue{transforms: transforms, value: value}
+	}
+	return transformed, nil
+}
+
+// Reports whether the filter transforms the record values.
+func isTransformed(f commonFilter) bool {
+	if len(f.values) == 0 {
+		return false
+	}
+	_, ok := f.values[0].(transformedFilterValue)
+	return ok
+}
@@ -0,0 +1,134 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFilters_transform(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+		"tags": {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	records := []map[string]interface{}{
+		{"name": " EDGE-1 ", "tags": []interface{}{" Prod"}},
+		{"name": "edge-2", "tags": []interface{}{"dev"}},
+		{"name": "core-1", "tags": []interface{}{"PROD "}},
+	}
+	testCases := []struct {
+		name     string
+		filter   map[string]interface{}
+		expected []string
+	}{
+		{
+			"WithoutTransform",
+			map[string]interface{}{"attribute": "name", "values": []interface{}{"^edge-"}, "match_by": "re"},
+			[]string{"edge-2"},
+		},
+		{
+			"ChainedTransforms",
+			map[string]interface{}{"attribute": "name", "values": []interface{}{"^edge-"}, "match_by": "re", "transform": []interface{}{"trim", "lower"}},
+			[]string{" EDGE-1 ", "edge-2"},
+		},
+		{
+			"ListElements",
+			map[string]interface{}{"attribute": "tags", "values": []interface{}{"prod"}, "transform": []interface{}{"trim"}},
+			[]string{" EDGE-1 ", "core-1"},
+		},
+		{
+			"Upper",
+			map[string]interface{}{"attribute": "name", "values": []interface{}{"CORE"}, "match_by": "substring", "transform": []interface{}{"upper"}},
+			[]string{"core-1"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{testCase.filter})
+			if err != nil {
+				t.Fatalf("expandFilters returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expected, names)
+		})
+	}
+}
+
+func TestExpandFilters_transformNotString(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"cores": {Type: schema.TypeInt},
+	}
+	_, err := expandFilters(recordSchema, []interface{}{
+		map[string]interface{}{
+			"attribute": "cores",
+			"values":    []interface{}{"2"},
+			"transform": []interface{}{"trim"},
+		},
+	})
+	assert.EqualError(t, err, "transform is not supported by field 'cores' of type TypeInt")
+}
+
+func TestApplyFilters_decodeTransforms(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name":  {Type: schema.TypeString},
+		"label": {Type: schema.TypeString},
+	}
+	records := []map[string]interface{}{
+		// team=network
+		{"name": "padded", "label": "dGVhbT1uZXR3b3Jr"},
+		// team=net
+		{"name": "unpadded", "label": "dGVhbT1uZXQ"},
+		{"name": "hex", "label": "7465616d3d6e6574"},
+		{"name": "invalid", "label": "team=net!"},
+	}
+	testCases := []struct {
+		name     string
+		filter   map[string]interface{}
+		expected []string
+	}{
+		{
+			"Base64",
+			map[string]interface{}{"attribute": "label", "values": []interface{}{"team=net"}, "match_by": "substring", "transform": []interface{}{"base64decode"}},
+			[]string{"padded", "unpadded"},
+		},
+		{
+			"Hex",
+			map[string]interface{}{"attribute": "label", "values": []interface{}{"TEAM=NET"}, "transform": []interface{}{"hexdecode", "upper"}},
+			[]string{"hex"},
+		},
+		{
+			"InvalidNegated",
+			map[string]interface{}{"attribute": "label", "values": []interface{}{"team=network"}, "match_by": "not_in_enum", "transform": []interface{}{"base64decode"}},
+			// The hex digits happen to be valid base64
+			[]string{"unpadded", "hex"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{testCase.filter})
+			if err != nil {
+				t.Fatalf("expandFilters returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expected, names, "Values which cannot be decoded do not match")
+		})
+	}
+}
+
+func TestBase64Decode(t *testing.T) {
+	decoded, ok := base64Decode("dGVhbT1uZXQ=")
+	assert.True(t, ok)
+	assert.Equal(t, "team=net", decoded)
+
+	_, ok = base64Decode("not base64")
+	assert.False(t, ok)
+	_, ok = hexDecode("7465616d3")
+	assert.False(t, ok, "Odd length hex is invalid")
+}
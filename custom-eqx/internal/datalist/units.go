@@ -0,0 +1,45 @@
+package datalist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Multipliers of the supported bit rate units, both decimal (Kbps = 1000 bps) and
+// binary (Kibps = 1024 bps). The longer units are listed first, as the units are
+// matched as suffixes.
+var bitRateUnits = []struct {
+	unit       string
+	multiplier float64
+}{
+	{"kibps", 1 << 10},
+	{"mibps", 1 << 20},
+	{"gibps", 1 << 30},
+	{"tibps", 1 << 40},
+	{"kbps", 1e3},
+	{"mbps", 1e6},
+	{"gbps", 1e9},
+	{"tbps", 1e12},
+	{"bps", 1},
+}
+
+// Parses a number with an optional bit rate unit suffix, e.g. "10Gbps" or "1.5 Mbps",
+// into the number of bits per second. Units are case-insensitive.
+func parseBitRate(value string) (float64, error) {
+	number := strings.TrimSpace(value)
+	multiplier := 1.
+	lower := strings.ToLower(number)
+	for _, u := range bitRateUnits {
+		if strings.HasSuffix(lower, u.unit) {
+			number = strings.TrimSpace(number[:len(number)-len(u.unit)])
+			multiplier = u.multiplier
+			break
+		}
+	}
+	floatValue, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse value as bit rate: %s", value)
+	}
+	return floatValue * multiplier, nil
+}
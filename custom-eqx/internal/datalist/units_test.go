@@ -0,0 +1,76 @@
+package datalist
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBitRate(t *testing.T) {
+	testCases := map[string]float64{
+		"1000":     1000,
+		"10bps":    10,
+		"1Kbps":    1e3,
+		"100Mbps":  1e8,
+		"1Gbps":    1e9,
+		"1.5 gbps": 1.5e9,
+		"2Tbps":    2e12,
+		"1Kibps":   1024,
+		"1Gibps":   1 << 30,
+	}
+
+	for value, expected := range testCases {
+		parsed, err := parseBitRate(value)
+		assert.NoError(t, err, "value %q", value)
+		assert.Equal(t, expected, parsed, "value %q", value)
+	}
+
+	_, err := parseBitRate("1Gb/s")
+	assert.Error(t, err)
+}
+
+func TestApplyFilters_units(t *testing.T) {
+	recordSchema := map[string]*schema.Schema{
+		"name": {
+			Type: schema.TypeString,
+		},
+		"bandwidth": {
+			Type: schema.TypeFloat,
+		},
+	}
+	records := []map[string]interface{}{
+		{"name": "link-1", "bandwidth": 1000000000.},
+		{"name": "link-2", "bandwidth": 100000000.},
+	}
+
+	testCases := []struct {
+		value         string
+		expectedNames []string
+	}{
+		{"1Gbps", []string{"link-1"}},
+		{"1000Mbps", []string{"link-1"}},
+		{"100Mbps", []string{"link-2"}},
+		{"1Gibps", nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			filters, err := expandFilters(recordSchema, []interface{}{
+				map[string]interface{}{
+					"attribute": "bandwidth",
+					"values":    []interface{}{testCase.value},
+					"match_by":  "units",
+				},
+			})
+			if err != nil {
+				t.Fatalf("expandFilters returned error: %s", err)
+			}
+			var names []string
+			for _, record := range applyFilters(recordSchema, records, filters) {
+				names = append(names, record["name"].(string))
+			}
+			assert.Equal(t, testCase.expectedNames, names)
+		})
+	}
+}
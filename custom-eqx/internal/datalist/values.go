@@ -2,24 +2,77 @@ package datalist
 
 import (
 	"math"
+	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// The clock used by the relative time filters, replaced in tests.
+var timeNow = time.Now
+
 func floatApproxEquals(a, b float64) bool {
-	return math.Abs(a-b) < 0.000001
+	return floatEqualsWithin(a, b, defaultFloatTolerance)
+}
+
+func floatEqualsWithin(a, b, tolerance float64) bool {
+	return math.Abs(a-b) < tolerance
 }
 
 func valueMatches(s *schema.Schema, value interface{}, filterValue interface{}, matchBy string) bool {
 	switch s.Type {
 	case schema.TypeString:
+		if v, ok := filterValue.(transformedFilterValue); ok {
+			transformed, ok := v.apply(value.(string))
+			if !ok {
+				return false
+			}
+			value, filterValue = transformed, v.value
+		}
 		switch matchBy {
 		case "substring":
 			return strings.Contains(value.(string), filterValue.(string))
 		case "re":
 			return filterValue.(*regexp.Regexp).MatchString(value.(string))
+		case "metro":
+			return MetroMatches(value.(string), filterValue.(string))
+		case "enum":
+			return filterValue.(enumFilterValue).matches(value.(string))
+		case "resolves":
+			return filterValue.(*hostnameResolver).resolves(value.(string))
+		case "not_resolves":
+			return !filterValue.(*hostnameResolver).resolves(value.(string))
+		case "id_in":
+			return filterValue.(idSetFilterValue).contains(value.(string))
+		case "json_path":
+			return filterValue.(jsonPathFilterValue).matches(value.(string))
+		case "str_between":
+			return filterValue.(strBetweenFilterValue).matches(value.(string))
+		case "fuzzy":
+			return filterValue.(fuzzyFilterValue).matches(value.(string))
+		case "not_in_enum":
+			return !filterValue.(enumSetFilterValue).contains(value.(string))
+		case "bool":
+			b, ok := parseBoolToken(value.(string))
+			return ok && b == filterValue.(bool)
+		case "in_file":
+			return filterValue.(valueListFile).contains(value.(string))
+		case "not_in_file":
+			return !filterValue.(valueListFile).contains(value.(string))
+		case "within_last", "after":
+			t, ok := parseTimestamp(value.(string))
+			return ok && t.After(filterValue.(time.Time))
+		case "before":
+			t, ok := parseTimestamp(value.(string))
+			return ok && t.Before(filterValue.(time.Time))
+		case "age_gt":
+			t, ok := parseTimestamp(value.(string))
+			return ok && timeNow().Sub(t) > filterValue.(time.Duration)
+		case "age_lt":
+			t, ok := parseTimestamp(value.(string))
+			return ok && timeNow().Sub(t) < filterValue.(time.Duration)
 		}
 		return strings.EqualFold(filterValue.(string), value.(string))
 
@@ -28,6 +81,9 @@ func valueMatches(s *schema.Schema, value interface{}, filterValue interface{},
 
 	case schema.TypeInt:
 		val := value.(int)
+		if matchBy == "mod" {
+			return filterValue.(modFilterValue).matches(val)
+		}
 		filter := filterValue.(int)
 		switch matchBy {
 		case "less_than":
@@ -42,19 +98,23 @@ func valueMatches(s *schema.Schema, value interface{}, filterValue interface{},
 		return val == filter
 
 	case schema.TypeFloat:
+		tolerance := defaultFloatTolerance
+		if v, ok := filterValue.(tolerantFloatValue); ok {
+			filterValue, tolerance = v.value, v.tolerance
+		}
 		val := value.(float64)
 		filter := filterValue.(float64)
 		switch matchBy {
 		case "less_than":
 			return val != 0. && (val < filter)
 		case "less_than_or_equal":
-			return val != 0. && ((val < filter) || floatApproxEquals(filter, val))
+			return val != 0. && ((val < filter) || floatEqualsWithin(filter, val, tolerance))
 		case "greater_than":
 			return val != 0. && (val > filter)
 		case "greater_than_or_equal":
-			return val != 0. && ((val > filter) || floatApproxEquals(filter, val))
+			return val != 0. && ((val > filter) || floatEqualsWithin(filter, val, tolerance))
 		}
-		return floatApproxEquals(filter, val)
+		return floatEqualsWithin(filter, val, tolerance)
 
 	case schema.TypeList:
 		listValues := value.([]interface{})
@@ -74,11 +134,52 @@ func valueMatches(s *schema.Schema, value interface{}, filterValue interface{},
 			result = result || valueDoesMatch
 		}
 		return result
+
+	case schema.TypeMap:
+		if key, ok := filterValue.(caseInsensitiveKey); ok {
+			return !mapHasKeyFold(value, string(key))
+		}
+		return !mapHasKey(value, filterValue.(string))
 	}
 
 	return false
 }
 
+// Reports whether the map, as flattened by FlattenRecord, contains the key.
+func mapHasKey(value interface{}, key string) bool {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		_, ok := m[key]
+		return ok
+	case map[string]string:
+		_, ok := m[key]
+		return ok
+	}
+	return false
+}
+
+// Reports whether the value is set: strings, lists, sets and maps are not empty and numbers
+// are not zero. Booleans are always considered set.
+func valuePresent(s *schema.Schema, value interface{}) bool {
+	switch s.Type {
+	case schema.TypeString:
+		return value.(string) != ""
+	case schema.TypeBool:
+		return true
+	case schema.TypeInt:
+		return value.(int) != 0
+	case schema.TypeFloat:
+		return value.(float64) != 0.
+	case schema.TypeList:
+		return len(value.([]interface{})) > 0
+	case schema.TypeSet:
+		return value.(*schema.Set).Len() > 0
+	case schema.TypeMap:
+		return reflect.ValueOf(value).Len() > 0
+	}
+	return false
+}
+
 func compareValues(s *schema.Schema, value1 interface{}, value2 interface{}) int {
 	switch s.Type {
 	case schema.TypeString:
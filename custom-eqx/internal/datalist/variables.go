@@ -0,0 +1,44 @@
+package datalist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Substitutes the values of filters referencing a provider-supplied variable with the
+// value of that variable. Filters which do not set `variable` are returned unchanged.
+func resolveFilterVariables(rawFilters []interface{}, variables map[string]string) ([]interface{}, error) {
+	resolved := make([]interface{}, len(rawFilters))
+	for i, rawFilter := range rawFilters {
+		f := rawFilter.(map[string]interface{})
+		name, _ := f["variable"].(string)
+		if name == "" {
+			resolved[i] = f
+			continue
+		}
+		if values, _ := f["values"].([]interface{}); len(values) > 0 {
+			return nil, fmt.Errorf("filter on '%s' cannot set both values and variable", f["attribute"])
+		}
+		value, ok := variables[name]
+		if !ok {
+			return nil, fmt.Errorf("filter variable '%s' is not supported, expected one of: %s", name, strings.Join(variableNames(variables), ", "))
+		}
+		r := make(map[string]interface{}, len(f))
+		for k, v := range f {
+			r[k] = v
+		}
+		r["values"] = []interface{}{value}
+		resolved[i] = r
+	}
+	return resolved, nil
+}
+
+func variableNames(variables map[string]string) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,88 @@
+package datalist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testVariablesResource(filterVariables func(meta interface{}) map[string]string) *schema.Resource {
+	return newTestResource(map[string]*schema.Schema{
+		"name": {Type: schema.TypeString},
+		"tags": {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+	}, []interface{}{
+		map[string]interface{}{"name": "dev-1", "tags": []interface{}{"staging"}},
+		map[string]interface{}{"name": "dev-2", "tags": []interface{}{"production"}},
+	}, func(config *ResourceConfig) {
+		config.FilterVariables = filterVariables
+	})
+}
+
+func TestNewResource_filterVariable(t *testing.T) {
+	// given
+	resource := testVariablesResource(func(meta interface{}) map[string]string {
+		return map[string]string{"environment": meta.(string)}
+	})
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{
+				"attribute": "tags",
+				"variable":  "environment",
+			},
+		},
+	})
+	// when
+	diags := resource.ReadContext(context.Background(), d, "production")
+	// then
+	assert.False(t, diags.HasError(), "read does not return errors: %v", diags)
+	devices := d.Get("devices").([]interface{})
+	assert.Len(t, devices, 1, "Filter matches the variable value")
+	assert.Equal(t, "dev-2", devices[0].(map[string]interface{})["name"])
+}
+
+func TestNewResource_filterVariableErrors(t *testing.T) {
+	tests := map[string]struct {
+		filterVariables func(meta interface{}) map[string]string
+		filter          map[string]interface{}
+		expected        string
+	}{
+		"variables not supported": {
+			filter:   map[string]interface{}{"attribute": "name", "variable": "environment"},
+			expected: "filter variable 'environment' is not supported",
+		},
+		"unknown variable": {
+			filterVariables: func(meta interface{}) map[string]string {
+				return map[string]string{"environment": "production"}
+			},
+			filter:   map[string]interface{}{"attribute": "name", "variable": "workspace"},
+			expected: "filter variable 'workspace' is not supported, expected one of: environment",
+		},
+		"values and variable": {
+			filterVariables: func(meta interface{}) map[string]string {
+				return map[string]string{"environment": "production"}
+			},
+			filter: map[string]interface{}{
+				"attribute": "name",
+				"values":    []interface{}{"dev-1"},
+				"variable":  "environment",
+			},
+			expected: "filter on 'name' cannot set both values and variable",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			resource := testVariablesResource(test.filterVariables)
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+				"filter": []interface{}{test.filter},
+			})
+			// when
+			diags := resource.ReadContext(context.Background(), d, nil)
+			// then
+			assert.True(t, diags.HasError(), "read returns an error")
+			assert.Contains(t, diags[0].Summary, test.expected)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Package jsonutil compares JSON documents by their canonical form, e.g. for the
+// attributes of resources and data sources holding JSON documents, which the API may
+// return with keys in a different order.
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Canonicalize returns the canonical form of a JSON document: object keys are
+// sorted and insignificant whitespace is removed. Numbers are kept as written.
+// Documents with data after their top-level value are invalid.
+func Canonicalize(s string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return "", err
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		return "", fmt.Errorf("invalid character after the top-level JSON value")
+	}
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// Equivalent reports whether two JSON documents have the same canonical form.
+// Invalid documents are only equivalent when they are equal.
+func Equivalent(a, b string) bool {
+	if a == b {
+		return true
+	}
+	canonicalA, err := Canonicalize(a)
+	if err != nil {
+		return false
+	}
+	canonicalB, err := Canonicalize(b)
+	if err != nil {
+		return false
+	}
+	return canonicalA == canonicalB
+}
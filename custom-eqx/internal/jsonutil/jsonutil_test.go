@@ -0,0 +1,46 @@
+package jsonutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	// given
+	input := `{ "b": [3, {"y": 1, "x": "<a&b>"}],
+		"a": 1.50 }`
+	// when
+	canonical, err := Canonicalize(input)
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1.50,"b":[3,{"x":"<a&b>","y":1}]}`, canonical, "Keys are sorted and whitespace removed")
+}
+
+func TestEquivalent(t *testing.T) {
+	// given
+	a := `{"hostname": "router", "settings": {"mtu": 1500, "lldp": true}}`
+	b := `{
+  "settings": {"lldp": true, "mtu": 1500},
+  "hostname": "router"
+}`
+	// when then
+	assert.True(t, Equivalent(a, b), "Documents with different key order are equivalent")
+	assert.False(t, Equivalent(a, `{"hostname": "router"}`), "Different documents are not equivalent")
+	assert.False(t, Equivalent(a, "not json"), "Invalid document is not equivalent")
+}
+
+func TestCanonicalize_trailingData(t *testing.T) {
+	for _, input := range []string{`{"a":1} garbage`, `{"a":1} {"b":2}`, `{"a":1}]`} {
+		// when
+		_, err := Canonicalize(input)
+		// then
+		assert.Error(t, err, "Document %q with trailing data is invalid", input)
+		assert.False(t, Equivalent(input, `{"a":1}`), "Document %q with trailing data is not equivalent", input)
+	}
+	// when
+	canonical, err := Canonicalize("{\"a\":1}\n\t ")
+	// then
+	assert.NoError(t, err, "Trailing whitespace is allowed")
+	assert.Equal(t, `{"a":1}`, canonical)
+}
@@ -0,0 +1,39 @@
+package equinix
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/artraf/equinix-custom-ne/custom-eqx/internal/jsonutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// suppressEquivalentJSONDiffs is a DiffSuppressFunc for attributes holding JSON
+// documents, or maps of them, ignoring differences in key ordering and whitespace.
+func suppressEquivalentJSONDiffs(k, old, new string, d *schema.ResourceData) bool {
+	return jsonutil.Equivalent(old, new)
+}
+
+// decodeJSON decodes the JSON document read from r into v. In strict mode, fields of
+// the document unknown to v are reported with a warning naming the document, to
+// surface API changes early, and are otherwise ignored.
+func decodeJSON(r io.Reader, v interface{}, strict bool, document string) error {
+	if !strict {
+		return json.NewDecoder(r).Decode(v)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(v)
+	if err == nil || !strings.HasPrefix(err.Error(), "json: unknown field ") {
+		return err
+	}
+	log.Printf("[WARN] Unexpected %s: %s, the API may have changed", document, strings.TrimPrefix(err.Error(), "json: "))
+	return json.Unmarshal(b, v)
+}
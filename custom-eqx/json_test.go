@@ -0,0 +1,64 @@
+package equinix
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressEquivalentJSONDiffs(t *testing.T) {
+	// given
+	old := `{"hostname": "router", "settings": {"mtu": 1500, "lldp": true}}`
+	new := `{"settings": {"lldp": true, "mtu": 1500}, "hostname": "router"}`
+	// when then
+	assert.True(t, suppressEquivalentJSONDiffs("vendor_configuration.settings", old, new, nil), "Diff is suppressed")
+	assert.False(t, suppressEquivalentJSONDiffs("vendor_configuration.settings", old, `{"hostname": "router"}`, nil), "Diff is not suppressed")
+	assert.False(t, suppressEquivalentJSONDiffs("vendor_configuration.siteId", "site-1", "site-2", nil), "Diff of plain values is not suppressed")
+}
+
+func TestDecodeJSON(t *testing.T) {
+	type document struct {
+		Name string `json:"name"`
+	}
+	testCases := []struct {
+		name     string
+		json     string
+		strict   bool
+		expected string
+		warning  bool
+	}{
+		{"KnownFields", `{"name": "dev-1"}`, true, "dev-1", false},
+		{"UnknownFields", `{"name": "dev-1", "status": "PROVISIONED"}`, true, "dev-1", true},
+		{"UnknownFieldsNotStrict", `{"name": "dev-1", "status": "PROVISIONED"}`, false, "dev-1", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			defer log.SetOutput(os.Stderr)
+			var v document
+			// when
+			err := decodeJSON(strings.NewReader(testCase.json), &v, testCase.strict, "device")
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, v.Name, "Known fields are decoded")
+			if testCase.warning {
+				assert.Contains(t, logs.String(), `[WARN] Unexpected device: unknown field "status"`)
+			} else {
+				assert.Empty(t, logs.String(), "No warning is logged")
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_invalid(t *testing.T) {
+	var v map[string]interface{}
+
+	assert.Error(t, decodeJSON(strings.NewReader(`{"name": `), &v, true, "device"))
+	assert.Error(t, decodeJSON(strings.NewReader(`["dev-1"]`), &v, true, "device"), "Type errors are not ignored")
+}
@@ -0,0 +1,39 @@
+package equinix
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsSink receives a measurement of every API request sent by the provider.
+// The status code is zero when the request failed without a response.
+type MetricsSink interface {
+	ObserveRequest(service string, statusCode int, duration time.Duration)
+}
+
+// metricsTransport is a RoundTripper reporting the outcome and duration of the
+// requests of a service to a MetricsSink.
+type metricsTransport struct {
+	service string
+	sink    MetricsSink
+	now     func() time.Time
+	next    http.RoundTripper
+}
+
+func newMetricsTransport(service string, sink MetricsSink, now func() time.Time, next http.RoundTripper) *metricsTransport {
+	if now == nil {
+		now = time.Now
+	}
+	return &metricsTransport{service: service, sink: sink, now: now, next: next}
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.now()
+	resp, err := t.next.RoundTrip(req)
+	statusCode := 0
+	if err == nil {
+		statusCode = resp.StatusCode
+	}
+	t.sink.ObserveRequest(t.service, statusCode, t.now().Sub(start))
+	return resp, err
+}
@@ -0,0 +1,62 @@
+package equinix
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type metricsObservation struct {
+	service    string
+	statusCode int
+	duration   time.Duration
+}
+
+type recordingMetricsSink struct {
+	observations []metricsObservation
+}
+
+func (s *recordingMetricsSink) ObserveRequest(service string, statusCode int, duration time.Duration) {
+	s.observations = append(s.observations, metricsObservation{service, statusCode, duration})
+}
+
+type clockRoundTripper struct {
+	clock *fakeClock
+	next  http.RoundTripper
+}
+
+func (t clockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.clock.Advance(250 * time.Millisecond)
+	return t.next.RoundTrip(req)
+}
+
+func TestMetricsTransport(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	clock := &fakeClock{now: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)}
+	sink := &recordingMetricsSink{}
+	config := Config{MetricsSink: sink, now: clock.Now}
+	client := &http.Client{Transport: config.serviceTransport("ne", clockRoundTripper{clock, http.DefaultTransport})}
+	// when
+	for _, path := range []string{"/", "/missing"} {
+		resp, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	_, err := client.Get("http://127.0.0.1:0")
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, []metricsObservation{
+		{"ne", http.StatusOK, 250 * time.Millisecond},
+		{"ne", http.StatusNotFound, 250 * time.Millisecond},
+		{"ne", 0, 250 * time.Millisecond},
+	}, sink.observations, "Every request is reported to the sink")
+}
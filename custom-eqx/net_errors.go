@@ -0,0 +1,73 @@
+package equinix
+
+import (
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// The classes of connection-level errors that RetryableNetErrors can list.
+const (
+	netErrorConnectionReset   = "connection_reset"
+	netErrorConnectionRefused = "connection_refused"
+	netErrorUnreachable       = "unreachable"
+	netErrorDNS               = "dns"
+	netErrorTimeout           = "timeout"
+	netErrorEOF               = "eof"
+)
+
+var netErrorClasses = []string{
+	netErrorConnectionReset,
+	netErrorConnectionRefused,
+	netErrorUnreachable,
+	netErrorDNS,
+	netErrorTimeout,
+	netErrorEOF,
+}
+
+// Returns the class of the connection-level error, or an empty string when it is
+// not one of the netErrorClasses.
+func classifyNetError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		return netErrorDNS
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return netErrorConnectionReset
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return netErrorConnectionRefused
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return netErrorUnreachable
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return netErrorEOF
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return netErrorTimeout
+	}
+	return ""
+}
+
+func isNetErrorClass(class string) bool {
+	for _, c := range netErrorClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether the connection-level error is of a class listed in the
+// RetryableNetErrors, which retry all errors when empty.
+func (c *Config) isRetryableNetError(err error) bool {
+	if len(c.RetryableNetErrors) == 0 {
+		return true
+	}
+	class := classifyNetError(err)
+	for _, retryable := range c.RetryableNetErrors {
+		if class != "" && class == retryable {
+			return true
+		}
+	}
+	return false
+}
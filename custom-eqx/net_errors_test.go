@@ -0,0 +1,85 @@
+package equinix
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testNetError(op string, err error) error {
+	return &url.Error{Op: "Get", URL: "https://api.equinix.com/ne/v1/devices", Err: &net.OpError{Op: op, Net: "tcp", Err: err}}
+}
+
+func TestClassifyNetError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"Reset", testNetError("read", &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET}), netErrorConnectionReset},
+		{"Refused", testNetError("dial", &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}), netErrorConnectionRefused},
+		{"Unreachable", testNetError("dial", &os.SyscallError{Syscall: "connect", Err: syscall.ENETUNREACH}), netErrorUnreachable},
+		{"DNS", testNetError("dial", &net.DNSError{Err: "no such host", Name: "api.equinix.com", IsNotFound: true}), netErrorDNS},
+		{"DNSTimeout", testNetError("dial", &net.DNSError{Err: "i/o timeout", Name: "api.equinix.com", IsTimeout: true}), netErrorDNS},
+		{"EOF", &url.Error{Op: "Get", URL: "https://api.equinix.com", Err: io.EOF}, netErrorEOF},
+		{"Timeout", &url.Error{Op: "Get", URL: "https://api.equinix.com", Err: context.DeadlineExceeded}, netErrorTimeout},
+		{"Other", errors.New("unexpected"), ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, classifyNetError(testCase.err))
+		})
+	}
+}
+
+func TestConfig_metalRetryPolicy_netErrors(t *testing.T) {
+	// given
+	reset := testNetError("read", &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET})
+	dns := testNetError("dial", &net.DNSError{Err: "no such host", Name: "api.equinix.com", IsNotFound: true})
+	allowlisted := Config{RetryableNetErrors: []string{netErrorConnectionReset}}
+	unrestricted := Config{}
+	// when
+	retryReset, errReset := allowlisted.metalRetryPolicy(context.Background(), nil, reset)
+	retryDNS, errDNS := allowlisted.metalRetryPolicy(context.Background(), nil, dns)
+	retryUnrestricted, _ := unrestricted.metalRetryPolicy(context.Background(), nil, dns)
+	// then
+	assert.NoError(t, errReset)
+	assert.True(t, retryReset, "Connection resets are retried when allowed")
+	assert.NoError(t, errDNS)
+	assert.False(t, retryDNS, "DNS failures are not retried when not allowed")
+	assert.True(t, retryUnrestricted, "All connection errors are retried without allowlist")
+}
+
+func TestConfig_metalRetryPolicy_netErrorsWithSubstrings(t *testing.T) {
+	// given
+	config := Config{
+		RetryableNetErrors:       []string{netErrorConnectionReset},
+		RetryableErrorSubstrings: []string{"no such host"},
+	}
+	// when
+	retry, err := config.metalRetryPolicy(context.Background(), nil, testNetError("dial", &net.DNSError{Err: "no such host", Name: "api.equinix.com", IsNotFound: true}))
+	// then
+	assert.NoError(t, err)
+	assert.True(t, retry, "Errors containing a retryable substring are retried regardless of their class")
+}
+
+func TestConfig_Load_invalidRetryableNetErrors(t *testing.T) {
+	// given
+	config := Config{
+		BaseURL:            DefaultBaseURL,
+		Token:              "token",
+		RetryableNetErrors: []string{netErrorConnectionReset, "no such host"},
+	}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Error(t, err, "Load returns an error")
+	assert.Contains(t, err.Error(), "'retryableNetErrors'")
+}
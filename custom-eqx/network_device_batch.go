@@ -0,0 +1,96 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/artraf/custom-ne-go"
+	"github.com/hashicorp/go-multierror"
+)
+
+const defaultNetworkDeviceBatchConcurrency = 4
+
+// networkDeviceCreator is the part of the Network Edge client used by the batch create.
+type networkDeviceCreator interface {
+	CreateDevice(device ne.Device) (*string, error)
+}
+
+// NetworkDeviceBatchOptions controls the batch creation of Network Edge devices with
+// BatchCreateNetworkDevices.
+type NetworkDeviceBatchOptions struct {
+	// Concurrency is the maximum number of creates in flight, defaults to
+	// defaultNetworkDeviceBatchConcurrency
+	Concurrency int
+	// CancelOnError stops launching creates after the first failure. Creates
+	// already in flight are completed, as the client calls cannot be interrupted
+	CancelOnError bool
+}
+
+// NetworkDeviceBatchResult is the outcome of the create of a single device.
+type NetworkDeviceBatchResult struct {
+	Device ne.Device
+	UUID   string
+	Err    error
+}
+
+// BatchCreateNetworkDevices creates the given Network Edge devices concurrently, e.g.
+// for programs provisioning fleets of devices, once the configuration is loaded. See
+// batchCreateNetworkDevices.
+func (c *Config) BatchCreateNetworkDevices(ctx context.Context, devices []ne.Device, options NetworkDeviceBatchOptions) ([]NetworkDeviceBatchResult, error) {
+	if c.ne == nil {
+		return nil, fmt.Errorf("configuration must be loaded before creating network devices")
+	}
+	return batchCreateNetworkDevices(ctx, c.ne, devices, options)
+}
+
+// batchCreateNetworkDevices creates the given devices concurrently, with at most
+// options.Concurrency creates in flight. Results are returned in the order of the
+// devices, along with an error aggregating the failures. Devices which were not
+// created because the context was done carry the context error.
+func batchCreateNetworkDevices(ctx context.Context, client networkDeviceCreator, devices []ne.Device, options NetworkDeviceBatchOptions) ([]NetworkDeviceBatchResult, error) {
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultNetworkDeviceBatchConcurrency
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]NetworkDeviceBatchResult, len(devices))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range devices {
+		results[i].Device = devices[i]
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		wg.Add(1)
+		go func(result *NetworkDeviceBatchResult) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			uuid, err := client.CreateDevice(result.Device)
+			if err != nil {
+				result.Err = err
+				if options.CancelOnError {
+					cancel()
+				}
+				return
+			}
+			result.UUID = ne.StringValue(uuid)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	errs := &multierror.Error{}
+	for _, result := range results {
+		if result.Err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("device %q: %w", ne.StringValue(result.Device.Name), result.Err))
+		}
+	}
+	return results, errs.ErrorOrNil()
+}
@@ -0,0 +1,125 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artraf/custom-ne-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedNetworkDeviceCreator struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	created     int
+	failNames   map[string]bool
+}
+
+func (m *mockedNetworkDeviceCreator) CreateDevice(device ne.Device) (*string, error) {
+	m.mu.Lock()
+	m.inFlight++
+	if m.inFlight > m.maxInFlight {
+		m.maxInFlight = m.inFlight
+	}
+	m.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+	if m.failNames[ne.StringValue(device.Name)] {
+		return nil, fmt.Errorf("quota exceeded")
+	}
+	m.created++
+	return ne.String("uuid-" + ne.StringValue(device.Name)), nil
+}
+
+func newTestNetworkDevices(count int) []ne.Device {
+	devices := make([]ne.Device, count)
+	for i := range devices {
+		devices[i] = ne.Device{Name: ne.String(fmt.Sprintf("device-%d", i))}
+	}
+	return devices
+}
+
+func TestNetworkDevice_batchCreate(t *testing.T) {
+	// given
+	client := &mockedNetworkDeviceCreator{failNames: map[string]bool{"device-3": true, "device-7": true}}
+	devices := newTestNetworkDevices(10)
+	// when
+	results, err := batchCreateNetworkDevices(context.Background(), client, devices, NetworkDeviceBatchOptions{Concurrency: 3})
+	// then
+	assert.LessOrEqual(t, client.maxInFlight, 3, "Concurrency limit is respected")
+	assert.Equal(t, 8, client.created, "All devices are attempted")
+	assert.Len(t, results, 10)
+	assert.Equal(t, "uuid-device-0", results[0].UUID, "Results are in device order")
+	assert.Error(t, results[3].Err)
+	assert.Error(t, err, "Failures are aggregated")
+	assert.Contains(t, err.Error(), `device "device-3": quota exceeded`)
+	assert.Contains(t, err.Error(), `device "device-7": quota exceeded`)
+}
+
+func TestNetworkDevice_batchCreate_cancelOnError(t *testing.T) {
+	// given
+	client := &mockedNetworkDeviceCreator{failNames: map[string]bool{"device-0": true}}
+	devices := newTestNetworkDevices(10)
+	// when
+	results, err := batchCreateNetworkDevices(context.Background(), client, devices, NetworkDeviceBatchOptions{Concurrency: 2, CancelOnError: true})
+	// then
+	assert.Error(t, err)
+	assert.Less(t, client.created, 9, "Creates are not launched after first failure")
+	assert.ErrorIs(t, results[9].Err, context.Canceled, "Not launched devices carry the context error")
+}
+
+func TestNetworkDevice_batchCreate_contextCanceled(t *testing.T) {
+	// given
+	client := &mockedNetworkDeviceCreator{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// when
+	results, err := batchCreateNetworkDevices(ctx, client, newTestNetworkDevices(3), NetworkDeviceBatchOptions{})
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, 0, client.created, "No device is created with canceled context")
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+}
+
+func TestConfig_BatchCreateNetworkDevices(t *testing.T) {
+	// given
+	var mu sync.Mutex
+	var created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/ne/v1/devices", r.URL.Path)
+		var body struct {
+			Name string `json:"virtualDeviceName"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		created = append(created, body.Name)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"uuid": "uuid-" + body.Name})
+	}))
+	defer server.Close()
+	config := Config{BaseURL: server.URL, Token: "token"}
+	_, err := config.BatchCreateNetworkDevices(context.Background(), newTestNetworkDevices(1), NetworkDeviceBatchOptions{})
+	assert.Error(t, err, "Devices cannot be created before the configuration is loaded")
+	assert.NoError(t, config.Load(context.Background()))
+	// when
+	results, err := config.BatchCreateNetworkDevices(context.Background(), newTestNetworkDevices(3), NetworkDeviceBatchOptions{Concurrency: 2})
+	// then
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"device-0", "device-1", "device-2"}, created, "Devices are created with the NE client")
+	for i, result := range results {
+		assert.Equal(t, fmt.Sprintf("uuid-device-%d", i), result.UUID)
+	}
+}
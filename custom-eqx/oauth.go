@@ -0,0 +1,125 @@
+package equinix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/equinix/oauth2-go"
+	xoauth2 "golang.org/x/oauth2"
+)
+
+const (
+	oauthTokenPath       = "/oauth2/v1/token"
+	oauthDefTokenTimeout = 3600
+)
+
+// clientCredentialsConfig describes the Equinix flavour of the oAuth2 client
+// credentials flow. It follows github.com/equinix/oauth2-go, which does not allow
+// to customize the token endpoint.
+type clientCredentialsConfig struct {
+	ClientID     string
+	ClientSecret string
+	// TokenURL is the absolute URL of the token endpoint
+	TokenURL string
+	// Scopes are requested with the token, the server defaults apply when empty
+	Scopes []string
+	// Audience is requested with the token when not empty
+	Audience string
+	// StrictDecoding warns of the fields of token responses which are not known
+	StrictDecoding bool
+}
+
+type clientCredentialsTokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	Scope        string `json:"scope,omitempty"`
+	Audience     string `json:"audience,omitempty"`
+}
+
+type clientCredentialsTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	TokenTimeout string `json:"token_timeout"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type clientCredentialsTokenError struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// TokenSource returns a TokenSource that returns the given token, which may be nil,
+// until it expires, automatically refreshing it as necessary using the provided
+// context and http client.
+func (c *clientCredentialsConfig) TokenSource(ctx context.Context, hc *http.Client, t *xoauth2.Token) xoauth2.TokenSource {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return xoauth2.ReuseTokenSource(t, &clientCredentialsTokenSource{ctx, c, hc})
+}
+
+type clientCredentialsTokenSource struct {
+	ctx    context.Context
+	conf   *clientCredentialsConfig
+	client *http.Client
+}
+
+func (s *clientCredentialsTokenSource) Token() (*xoauth2.Token, error) {
+	body, err := json.Marshal(clientCredentialsTokenRequest{
+		GrantType:    "client_credentials",
+		ClientID:     s.conf.ClientID,
+		ClientSecret: s.conf.ClientSecret,
+		Scope:        strings.Join(s.conf.Scopes, " "),
+		Audience:     s.conf.Audience,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.conf.TokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: failed to fetch token: %s", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-agent", "equinix/oauth2-go")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: failed to fetch token: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respError := clientCredentialsTokenError{}
+		_ = decodeJSON(resp.Body, &respError, s.conf.StrictDecoding, "token error response")
+		return nil, oauth2.Error{Code: respError.ErrorCode, Message: respError.ErrorMessage}
+	}
+	result := clientCredentialsTokenResponse{}
+	if err := decodeJSON(resp.Body, &result, s.conf.StrictDecoding, "token response"); err != nil {
+		return nil, fmt.Errorf("oauth2: failed to decode token response: %s", err)
+	}
+
+	token := xoauth2.Token{
+		AccessToken:  result.AccessToken,
+		TokenType:    "Bearer",
+		RefreshToken: result.RefreshToken,
+	}
+	timeout, err := strconv.Atoi(result.TokenTimeout)
+	if err != nil {
+		timeout = oauthDefTokenTimeout
+	}
+	if timeout != 0 {
+		token.Expiry = time.Now().Add(time.Duration(timeout) * time.Second)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2: server response missing access_token")
+	}
+	return &token, nil
+}
@@ -0,0 +1,68 @@
+// Package oteltrace provides a RequestTracer of the Equinix provider that starts an
+// OpenTelemetry span per API request. It is kept apart from the provider package, so
+// that the provider does not depend on OpenTelemetry.
+package oteltrace
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/artraf/equinix-custom-ne/custom-eqx/oteltrace"
+
+// RequestIDKey is the span attribute holding the X-Request-Id of the API response.
+const RequestIDKey = attribute.Key("equinix.request_id")
+
+// ServiceKey is the span attribute holding the name of the API service.
+const ServiceKey = attribute.Key("equinix.service")
+
+// Tracer starts the spans of API requests with a tracer of the given provider.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// New creates a Tracer using the given tracer provider.
+func New(provider trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: provider.Tracer(instrumentationName)}
+}
+
+// TraceTransport implements the RequestTracer interface of the provider.
+func (t *Tracer) TraceTransport(service string, next http.RoundTripper) http.RoundTripper {
+	return &transport{tracer: t.tracer, service: service, next: next}
+}
+
+type transport struct {
+	tracer  trace.Tracer
+	service string
+	next    http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), "Equinix "+t.service+" "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			ServiceKey.String(t.service),
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.NetPeerNameKey.String(req.URL.Hostname()),
+		))
+	defer span.End()
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+		span.SetAttributes(RequestIDKey.String(requestID))
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
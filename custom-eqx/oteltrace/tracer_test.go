@@ -0,0 +1,70 @@
+package oteltrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	equinix "github.com/artraf/equinix-custom-ne/custom-eqx"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+var _ equinix.RequestTracer = (*Tracer)(nil)
+
+func TestTracer(t *testing.T) {
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-"+r.Method)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	client := &http.Client{Transport: New(provider).TraceTransport("ne", http.DefaultTransport)}
+	// when
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	req, _ := http.NewRequest(http.MethodDelete, server.URL, nil)
+	resp, err = client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	// then
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2, "One span is started per request")
+	assert.Equal(t, "Equinix ne GET", spans[0].Name)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		ServiceKey.String("ne"),
+		semconv.HTTPMethodKey.String(http.MethodGet),
+		semconv.NetPeerNameKey.String(serverURL.Hostname()),
+		semconv.HTTPStatusCodeKey.Int(http.StatusOK),
+		RequestIDKey.String("req-GET"),
+	}, spans[0].Attributes)
+	assert.Contains(t, spans[1].Attributes, semconv.HTTPStatusCodeKey.Int(http.StatusNotFound))
+	assert.Contains(t, spans[1].Attributes, RequestIDKey.String("req-DELETE"))
+}
+
+func TestTracer_requestError(t *testing.T) {
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	client := &http.Client{Transport: New(provider).TraceTransport("ecx", http.DefaultTransport)}
+	// when
+	_, err := client.Get(server.URL)
+	// then
+	assert.Error(t, err)
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "Error", spans[0].Status.Code.String(), "Failed request marks the span as failed")
+	assert.Len(t, spans[0].Events, 1, "Error is recorded on the span")
+}
@@ -0,0 +1,57 @@
+// Package promsink provides a MetricsSink of the Equinix provider that exports the
+// API request metrics to Prometheus. It is kept apart from the provider package, so
+// that the provider does not depend on the Prometheus client.
+package promsink
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "equinix_provider"
+
+// Sink counts the API requests and observes their durations, labelled by service
+// and status. The status is the HTTP status code, or "error" for requests failed
+// without a response.
+type Sink struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// New creates a Sink and registers its collectors with the given registerer.
+func New(registerer prometheus.Registerer) (*Sink, error) {
+	s := &Sink{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of API requests sent by the provider.",
+		}, []string{"service", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of API requests sent by the provider.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "status"}),
+	}
+	if err := registerer.Register(s.requests); err != nil {
+		return nil, err
+	}
+	if err := registerer.Register(s.duration); err != nil {
+		// Nothing is left registered, so that New can be called again
+		registerer.Unregister(s.requests)
+		return nil, err
+	}
+	return s, nil
+}
+
+// ObserveRequest implements the MetricsSink interface of the provider.
+func (s *Sink) ObserveRequest(service string, statusCode int, duration time.Duration) {
+	status := "error"
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	s.requests.WithLabelValues(service, status).Inc()
+	s.duration.WithLabelValues(service, status).Observe(duration.Seconds())
+}
@@ -0,0 +1,87 @@
+package promsink
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	equinix "github.com/artraf/equinix-custom-ne/custom-eqx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ equinix.MetricsSink = (*Sink)(nil)
+
+func TestSink(t *testing.T) {
+	// given
+	registry := prometheus.NewRegistry()
+	sink, err := New(registry)
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	config := equinix.Config{BaseURL: server.URL, Token: "token", MetricsSink: sink}
+	assert.NoError(t, config.Load(context.Background()))
+	neClient, err := config.ServiceHTTPClient("ne")
+	assert.NoError(t, err)
+	ecxClient, err := config.ServiceHTTPClient("ecx")
+	assert.NoError(t, err)
+	// when
+	for _, path := range []string{"/", "/", "/missing"} {
+		resp, err := neClient.Get(server.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	_, err = ecxClient.Get(dead.URL)
+	assert.Error(t, err)
+	// then
+	expected := `
+# HELP equinix_provider_requests_total Number of API requests sent by the provider.
+# TYPE equinix_provider_requests_total counter
+equinix_provider_requests_total{service="ecx",status="error"} 1
+equinix_provider_requests_total{service="ne",status="200"} 2
+equinix_provider_requests_total{service="ne",status="404"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "equinix_provider_requests_total"))
+	assert.Equal(t, 3, testutil.CollectAndCount(sink.duration), "Durations are observed per service and status")
+}
+
+func TestNew_alreadyRegistered(t *testing.T) {
+	// given
+	registry := prometheus.NewRegistry()
+	_, err := New(registry)
+	assert.NoError(t, err)
+	// when
+	_, err = New(registry)
+	// then
+	assert.Error(t, err, "Registering the collectors twice fails")
+}
+
+func TestNew_histogramConflict(t *testing.T) {
+	// given
+	registry := prometheus.NewRegistry()
+	conflict := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Conflicting collector.",
+	})
+	assert.NoError(t, registry.Register(conflict))
+	// when
+	_, err := New(registry)
+	// then
+	assert.Error(t, err, "Registering the histogram fails")
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "Number of API requests sent by the provider.",
+	}, []string{"service", "status"})
+	assert.NoError(t, registry.Register(requests), "Counter is unregistered")
+}
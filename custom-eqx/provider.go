@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/artraf/equinix-custom-ne/custom-eqx/internal/datalist"
 	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
 	"github.com/equinix/ecx-go/v2"
 	"github.com/equinix/rest-go"
@@ -26,12 +27,13 @@ var (
 )
 
 const (
-	endpointEnvVar       = "EQUINIX_API_ENDPOINT"
-	clientIDEnvVar       = "EQUINIX_API_CLIENTID"
-	clientSecretEnvVar   = "EQUINIX_API_CLIENTSECRET"
-	clientTokenEnvVar    = "EQUINIX_API_TOKEN"
-	clientTimeoutEnvVar  = "EQUINIX_API_TIMEOUT"
-	metalAuthTokenEnvVar = "METAL_AUTH_TOKEN"
+	endpointEnvVar         = "EQUINIX_API_ENDPOINT"
+	clientIDEnvVar         = "EQUINIX_API_CLIENTID"
+	clientSecretEnvVar     = "EQUINIX_API_CLIENTSECRET"
+	clientTokenEnvVar      = "EQUINIX_API_TOKEN"
+	clientTimeoutEnvVar    = "EQUINIX_API_TIMEOUT"
+	metalAuthTokenEnvVar   = "METAL_AUTH_TOKEN"
+	credentialSourceEnvVar = "EQUINIX_API_CREDENTIAL_SOURCE"
 )
 
 // resourceDataProvider provies interface to schema.ResourceData
@@ -78,6 +80,25 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc(metalAuthTokenEnvVar, ""),
 				Description: "The Equinix Metal API auth key for API operations",
 			},
+			"credential_source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(credentialSourceEnvVar, ""),
+				ValidateFunc: validation.StringInSlice([]string{credentialSourceKeyring}, false),
+				Description:  "Source of the client secret or token that are not set in the configuration. The only supported value is `keyring`, which reads them from the OS keyring",
+			},
+			"keyring_service": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     defaultKeyringService,
+				Description: fmt.Sprintf("The OS keyring service name under which credentials are stored. Defaults to %s", defaultKeyringService),
+			},
+			"keyring_account": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     defaultKeyringAccount,
+				Description: fmt.Sprintf("The OS keyring account name under which credentials are stored. Defaults to %s", defaultKeyringAccount),
+			},
 			"request_timeout": {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -88,8 +109,8 @@ func Provider() *schema.Provider {
 			"response_max_page_size": {
 				Type:         schema.TypeInt,
 				Optional:     true,
-				ValidateFunc: validation.IntAtLeast(100),
-				Description:  "The maximum number of records in a single response for REST queries that produce paginated responses",
+				ValidateFunc: validation.IntBetween(100, MaxPageSize),
+				Description:  fmt.Sprintf("The maximum number of records in a single response for REST queries that produce paginated responses. Defaults to %d", DefaultPageSize),
 			},
 			"max_retries": {
 				Type:     schema.TypeInt,
@@ -106,6 +127,7 @@ func Provider() *schema.Provider {
 			"eqx-custom-ne_network_account":            dataSourceNetworkAccount(),
 			"eqx-custom-ne_network_device":             dataSourceNetworkDevice(),
 			"eqx-custom-ne_network_device_type":        dataSourceNetworkDeviceType(),
+			"eqx-custom-ne_network_devices":            dataSourceNetworkDevices(),
 			"eqx-custom-ne_network_device_software":    dataSourceNetworkDeviceSoftware(),
 			"eqx-custom-ne_network_device_platform":    dataSourceNetworkDevicePlatform(),
 		},
@@ -126,6 +148,13 @@ func Provider() *schema.Provider {
 		},
 	}
 
+	for _, r := range provider.DataSourcesMap {
+		withDeprecationWarnings(r)
+	}
+	for _, r := range provider.ResourcesMap {
+		withDeprecationWarnings(r)
+	}
+
 	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return configureProvider(ctx, d, provider)
 	}
@@ -137,20 +166,7 @@ type providerMeta struct {
 }
 
 func configureProvider(ctx context.Context, d *schema.ResourceData, p *schema.Provider) (interface{}, diag.Diagnostics) {
-	mrws := d.Get("max_retry_wait_seconds").(int)
-	rt := d.Get("request_timeout").(int)
-
-	config := Config{
-		AuthToken:      d.Get("auth_token").(string),
-		BaseURL:        d.Get("endpoint").(string),
-		ClientID:       d.Get("client_id").(string),
-		ClientSecret:   d.Get("client_secret").(string),
-		Token:          d.Get("token").(string),
-		RequestTimeout: time.Duration(rt) * time.Second,
-		PageSize:       d.Get("response_max_page_size").(int),
-		MaxRetries:     d.Get("max_retries").(int),
-		MaxRetryWait:   time.Duration(mrws) * time.Second,
-	}
+	config := newProviderConfig(d.Get)
 	meta := providerMeta{}
 
 	if err := d.GetProviderMeta(&meta); err != nil {
@@ -170,7 +186,28 @@ func configureProvider(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 	if err := config.Load(stopCtx); err != nil {
 		return nil, diag.FromErr(err)
 	}
-	return &config, nil
+	return config, nil
+}
+
+// Returns the configuration of the provider arguments read with get.
+func newProviderConfig(get func(key string) interface{}) *Config {
+	mrws := get("max_retry_wait_seconds").(int)
+	rt := get("request_timeout").(int)
+
+	return &Config{
+		AuthToken:        get("auth_token").(string),
+		BaseURL:          get("endpoint").(string),
+		ClientID:         get("client_id").(string),
+		ClientSecret:     get("client_secret").(string),
+		Token:            get("token").(string),
+		CredentialSource: get("credential_source").(string),
+		KeyringService:   get("keyring_service").(string),
+		KeyringAccount:   get("keyring_account").(string),
+		RequestTimeout:   time.Duration(rt) * time.Second,
+		PageSize:         get("response_max_page_size").(int),
+		MaxRetries:       get("max_retries").(int),
+		MaxRetryWait:     time.Duration(mrws) * time.Second,
+	}
 }
 
 var resourceDefaultTimeouts = &schema.ResourceTimeout{
@@ -231,6 +268,22 @@ func stringIsMetroCode() schema.SchemaValidateFunc {
 	return validation.StringMatch(regexp.MustCompile("^[A-Z]{2}$"), "MetroCode must consist of two capital letters")
 }
 
+// stringIsMetroCodeOrName accepts metro codes in any case and the metro names known to
+// datalist.NormalizeMetroCode, e.g. dc or Ashburn.
+func stringIsMetroCodeOrName() schema.SchemaValidateFunc {
+	metroCode := regexp.MustCompile("^[A-Z]{2}$")
+	return func(i interface{}, k string) ([]string, []error) {
+		v, ok := i.(string)
+		if !ok {
+			return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+		}
+		if !metroCode.MatchString(datalist.NormalizeMetroCode(v)) {
+			return nil, []error{fmt.Errorf("%s must be a metro code of two letters or a metro name, got: %q", k, v)}
+		}
+		return nil, nil
+	}
+}
+
 func stringIsEmailAddress() schema.SchemaValidateFunc {
 	return validation.StringMatch(regexp.MustCompile("^[^ @]+@[^ @]+$"), "not valid email address")
 }
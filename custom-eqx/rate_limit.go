@@ -0,0 +1,89 @@
+package equinix
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the API quota state reported by the rate limit headers of a response.
+type RateLimit struct {
+	Service   string
+	Limit     int
+	Remaining int
+	// Reset is the time at which the quota is replenished, zero when not reported
+	Reset time.Time
+}
+
+// rateLimitState holds the most recent rate limit reported by any of the services.
+type rateLimitState struct {
+	mu   sync.RWMutex
+	last *RateLimit
+}
+
+func (s *rateLimitState) get() *RateLimit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.last == nil {
+		return nil
+	}
+	last := *s.last
+	return &last
+}
+
+func (s *rateLimitState) set(rateLimit RateLimit) {
+	s.mu.Lock()
+	s.last = &rateLimit
+	s.mu.Unlock()
+}
+
+// parseRateLimit reads the X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset headers. The reset is given in seconds since the epoch.
+// It returns false when the limit and remaining headers are not present.
+func parseRateLimit(service string, header http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	rateLimit := RateLimit{Service: service, Limit: limit, Remaining: remaining}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(reset, 0)
+	}
+	return rateLimit, true
+}
+
+// rateLimitTransport is a RoundTripper capturing the rate limit headers of the
+// responses. A warning is logged when the remaining quota drops below the threshold.
+type rateLimitTransport struct {
+	service   string
+	state     *rateLimitState
+	threshold int
+	next      http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if rateLimit, ok := parseRateLimit(t.service, resp.Header); ok {
+		t.state.set(rateLimit)
+		if rateLimit.Remaining < t.threshold {
+			log.Printf("[WARN] %s API rate limit is almost exhausted: %d of %d requests remaining, resets at %s",
+				t.service, rateLimit.Remaining, rateLimit.Limit, rateLimit.Reset.Format(time.RFC3339))
+		}
+	}
+	return resp, err
+}
+
+// LastRateLimit returns the rate limit reported by the most recent API response
+// carrying rate limit headers, or nil when none was received.
+func (c *Config) LastRateLimit() *RateLimit {
+	return c.rateLimit.get()
+}
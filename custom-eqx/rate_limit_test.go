@@ -0,0 +1,59 @@
+package equinix
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_LastRateLimit(t *testing.T) {
+	// given
+	remaining := 100
+	reset := time.Date(2022, 12, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remaining -= 50
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	config := Config{BaseURL: server.URL, Token: "token", RateLimitWarningThreshold: 10}
+	assert.NoError(t, config.Load(context.Background()))
+	assert.Nil(t, config.LastRateLimit(), "No rate limit is known before requests")
+	// when
+	_, err := config.ne.GetSSHPublicKeys()
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, &RateLimit{Service: "ne", Limit: 100, Remaining: 50, Reset: reset.Local()}, config.LastRateLimit(), "Rate limit is captured")
+	assert.NotContains(t, logs.String(), "rate limit is almost exhausted", "No warning above threshold")
+
+	// when
+	_, err = config.ne.GetSSHPublicKeys()
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 0, config.LastRateLimit().Remaining, "Latest rate limit is captured")
+	assert.Contains(t, logs.String(), "[WARN] ne API rate limit is almost exhausted: 0 of 100 requests remaining")
+}
+
+func TestParseRateLimit_missingHeaders(t *testing.T) {
+	// given
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "100")
+	// when
+	_, ok := parseRateLimit("ne", header)
+	// then
+	assert.False(t, ok, "Rate limit is not parsed without remaining header")
+}
@@ -4,10 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 
 	"github.com/artraf/custom-ne-go"
-	"github.com/equinix/rest-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -243,11 +241,8 @@ func resourceNetworkACLTemplateRead(ctx context.Context, d *schema.ResourceData,
 	var diags diag.Diagnostics
 	template, err := client.GetACLTemplate(d.Id())
 	if err != nil {
-		if restErr, ok := err.(rest.Error); ok {
-			if restErr.HTTPCode == http.StatusNotFound {
-				d.SetId("")
-				return diags
-			}
+		if m.(*Config).removeIfNotFound(d, err) {
+			return diags
 		}
 		return diag.FromErr(err)
 	}
@@ -156,6 +156,9 @@ func resourceNetworkBGPRead(ctx context.Context, d *schema.ResourceData, m inter
 	var diags diag.Diagnostics
 	bgp, err := client.GetBGPConfiguration(d.Id())
 	if err != nil {
+		if m.(*Config).removeIfNotFound(d, err) {
+			return diags
+		}
 		return diag.FromErr(err)
 	}
 	if err := updateNetworkBGPResource(bgp, d); err != nil {
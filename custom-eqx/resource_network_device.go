@@ -456,10 +456,11 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 			Description: neDeviceDescriptions["Interfaces"],
 		},
 		neDeviceSchemaNames["VendorConfiguration"]: {
-			Type:     schema.TypeMap,
-			Optional: true,
-			Computed: true,
-			ForceNew: true,
+			Type:             schema.TypeMap,
+			Optional:         true,
+			Computed:         true,
+			ForceNew:         true,
+			DiffSuppressFunc: suppressEquivalentJSONDiffs,
 			Elem: &schema.Schema{
 				Type:         schema.TypeString,
 				ValidateFunc: validation.StringIsNotEmpty,
@@ -648,10 +649,11 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 						Description: neDeviceDescriptions["Interfaces"],
 					},
 					neDeviceSchemaNames["VendorConfiguration"]: {
-						Type:     schema.TypeMap,
-						Optional: true,
-						Computed: true,
-						ForceNew: true,
+						Type:             schema.TypeMap,
+						Optional:         true,
+						Computed:         true,
+						ForceNew:         true,
+						DiffSuppressFunc: suppressEquivalentJSONDiffs,
 						Elem: &schema.Schema{
 							Type:         schema.TypeString,
 							ValidateFunc: validation.StringIsNotEmpty,
@@ -939,6 +941,9 @@ func resourceNetworkDeviceRead(ctx context.Context, d *schema.ResourceData, m in
 	var primary, secondary *ne.Device
 	primary, err = client.GetDevice(d.Id())
 	if err != nil {
+		if m.(*Config).removeIfNotFound(d, err) {
+			return diags
+		}
 		return diag.Errorf("cannot fetch primary network device due to %v", err)
 	}
 	if isStringInSlice(ne.StringValue(primary.Status), []string{ne.DeviceStateDeprovisioning, ne.DeviceStateDeprovisioned}) {
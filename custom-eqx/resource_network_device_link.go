@@ -247,10 +247,10 @@ func resourceNetworkDeviceLinkRead(ctx context.Context, d *schema.ResourceData,
 	var diags diag.Diagnostics
 	link, err := client.GetDeviceLinkGroup(d.Id())
 	if err != nil {
-		if isRestNotFoundError(err) {
-			d.SetId("")
-			return nil
+		if m.(*Config).removeIfNotFound(d, err) {
+			return diags
 		}
+		return diag.FromErr(err)
 	}
 	for i, linkDevice := range link.Devices {
 		device, err := client.GetDevice(ne.StringValue(linkDevice.DeviceID))
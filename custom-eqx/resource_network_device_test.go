@@ -10,6 +10,7 @@ import (
 
 	"github.com/artraf/custom-ne-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -445,3 +446,39 @@ func TestNetworkDevice_AdditionalBandwidthStatusWaitConfiguration(t *testing.T)
 	assert.Equal(t, timeout, waitConfig.Timeout, "Additional bandwidth status wait configuration timeout matches")
 	assert.Equal(t, delay, waitConfig.MinTimeout, "Additional bandwidth wait configuration min timeout matches")
 }
+
+func TestNetworkDevice_vendorConfigurationJSONDiff(t *testing.T) {
+	testCases := []struct {
+		name         string
+		settings     string
+		expectedDiff bool
+	}{
+		{"Equivalent", `{"lldp":true,"mtu":1500}`, false},
+		{"Changed", `{"lldp":false,"mtu":1500}`, true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			state := &terraform.InstanceState{
+				ID: "uuid",
+				Attributes: map[string]string{
+					neDeviceSchemaNames["IsBYOL"]:        "false",
+					neDeviceSchemaNames["IsSelfManaged"]: "false",
+					"vendor_configuration.%":             "1",
+					"vendor_configuration.settings":      `{"mtu": 1500, "lldp": true}`,
+				},
+			}
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				neDeviceSchemaNames["VendorConfiguration"]: map[string]interface{}{
+					"settings": testCase.settings,
+				},
+			})
+			// when
+			diff, err := resourceNetworkDevice().Diff(context.Background(), state, config, nil)
+			// then
+			assert.NoError(t, err)
+			attribute, ok := diff.Attributes["vendor_configuration.settings"]
+			assert.Equal(t, testCase.expectedDiff, ok && attribute.Old != attribute.New, "Vendor configuration JSON diff")
+		})
+	}
+}
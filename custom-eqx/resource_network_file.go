@@ -4,11 +4,9 @@ import (
 	"context"
 	"fmt"
 	"github.com/artraf/custom-ne-go"
-	"github.com/equinix/rest-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"net/http"
 	"strings"
 )
 
@@ -132,11 +130,8 @@ func resourceNetworkFileRead(ctx context.Context, d *schema.ResourceData, m inte
 	var diags diag.Diagnostics
 	file, err := client.GetFile(d.Id())
 	if err != nil {
-		if restErr, ok := err.(rest.Error); ok {
-			if restErr.HTTPCode == http.StatusNotFound {
-				d.SetId("")
-				return diags
-			}
+		if m.(*Config).removeIfNotFound(d, err) {
+			return diags
 		}
 		return diag.FromErr(err)
 	}
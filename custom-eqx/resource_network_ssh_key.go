@@ -3,7 +3,6 @@ package equinix
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/artraf/custom-ne-go"
@@ -94,11 +93,8 @@ func resourceNetworkSSHKeyRead(ctx context.Context, d *schema.ResourceData, m in
 	var diags diag.Diagnostics
 	key, err := client.GetSSHPublicKey(d.Id())
 	if err != nil {
-		if restErr, ok := err.(rest.Error); ok {
-			if restErr.HTTPCode == http.StatusNotFound {
-				d.SetId("")
-				return nil
-			}
+		if m.(*Config).removeIfNotFound(d, err) {
+			return diags
 		}
 		return diag.FromErr(err)
 	}
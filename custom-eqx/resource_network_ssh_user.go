@@ -107,6 +107,9 @@ func resourceNetworkSSHUserRead(ctx context.Context, d *schema.ResourceData, m i
 	var diags diag.Diagnostics
 	user, err := client.GetSSHUser(d.Id())
 	if err != nil {
+		if m.(*Config).removeIfNotFound(d, err) {
+			return diags
+		}
 		return diag.FromErr(err)
 	}
 	if err := updateNetworkSSHUserResource(user, d); err != nil {
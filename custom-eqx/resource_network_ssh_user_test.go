@@ -1,9 +1,12 @@
 package equinix
 
 import (
+	"context"
+	"net/http"
 	"testing"
 
 	"github.com/artraf/custom-ne-go"
+	"github.com/equinix/rest-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,3 +49,52 @@ func TestNetworkSSHUser_updateResourceData(t *testing.T) {
 	assert.Equal(t, ne.StringValue(input.Password), d.Get(networkSSHUserSchemaNames["Password"]), "Password matches")
 	assert.Equal(t, input.DeviceUUIDs, expandSetToStringList(d.Get(networkSSHUserSchemaNames["DeviceUUIDs"]).(*schema.Set)), "DeviceUUIDs matches")
 }
+
+type mockNESSHUserClient struct {
+	ne.Client
+	GetSSHUserFn func(uuid string) (*ne.SSHUser, error)
+}
+
+func (m *mockNESSHUserClient) GetSSHUser(uuid string) (*ne.SSHUser, error) {
+	return m.GetSSHUserFn(uuid)
+}
+
+func TestNetworkSSHUser_read_notFound(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		// given
+		config := &Config{
+			StrictNotFound: strict,
+			ne: &mockNESSHUserClient{GetSSHUserFn: func(uuid string) (*ne.SSHUser, error) {
+				return nil, rest.Error{HTTPCode: http.StatusNotFound, Message: "not found"}
+			}},
+		}
+		d := schema.TestResourceDataRaw(t, resourceNetworkSSHUser().Schema, map[string]interface{}{})
+		d.SetId("user-uuid")
+		// when
+		diags := resourceNetworkSSHUserRead(context.Background(), d, config)
+		// then
+		if strict {
+			assert.True(t, diags.HasError(), "Not found is returned as error in strict mode")
+			assert.Equal(t, "user-uuid", d.Id(), "Resource is kept in strict mode")
+		} else {
+			assert.False(t, diags.HasError(), "Not found is not an error")
+			assert.Empty(t, d.Id(), "Resource is removed from state")
+		}
+	}
+}
+
+func TestNetworkSSHUser_read_error(t *testing.T) {
+	// given
+	config := &Config{
+		ne: &mockNESSHUserClient{GetSSHUserFn: func(uuid string) (*ne.SSHUser, error) {
+			return nil, rest.Error{HTTPCode: http.StatusInternalServerError, Message: "failure"}
+		}},
+	}
+	d := schema.TestResourceDataRaw(t, resourceNetworkSSHUser().Schema, map[string]interface{}{})
+	d.SetId("user-uuid")
+	// when
+	diags := resourceNetworkSSHUserRead(context.Background(), d, config)
+	// then
+	assert.True(t, diags.HasError(), "Other errors are returned")
+	assert.Equal(t, "user-uuid", d.Id(), "Resource is kept on other errors")
+}
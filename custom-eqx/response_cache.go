@@ -0,0 +1,154 @@
+package equinix
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// responseCacheTransport is a RoundTripper serving successful GET responses from
+// the cache for the TTL after they were received, keyed by method, URL and
+// credentials, so that responses are not served to requests with other credentials,
+// e.g. once they are rotated. Requests and responses with a Cache-Control: no-store
+// header are not cached. Any other request is sent as it is and clears the cache, as
+// it may change the cached resources.
+type responseCacheTransport struct {
+	cache *responseCache
+	// The request headers holding the credentials
+	credentialHeaders []string
+	next              http.RoundTripper
+}
+
+// responseCache holds the responses of a service, shared by all of its clients so
+// that the mutations sent by any of them clear it.
+type responseCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	status     int
+	header     http.Header
+	body       []byte
+	receivedAt time.Time
+}
+
+func newResponseCache(ttl time.Duration, now func() time.Time) *responseCache {
+	if now == nil {
+		now = time.Now
+	}
+	return &responseCache{ttl: ttl, now: now, entries: map[string]cachedResponse{}}
+}
+
+func newResponseCacheTransport(ttl time.Duration, now func() time.Time, next http.RoundTripper) *responseCacheTransport {
+	return &responseCacheTransport{cache: newResponseCache(ttl, now), next: next}
+}
+
+// Returns the response cached under the key, dropping it once expired.
+func (c *responseCache) get(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok && c.expired(entry) {
+		delete(c.entries, key)
+		return cachedResponse{}, false
+	}
+	return entry, ok
+}
+
+// Caches the response under the key, dropping the expired responses so that the
+// cache does not grow with responses which are never requested again.
+func (c *responseCache) put(key string, entry cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if c.expired(e) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry
+}
+
+func (c *responseCache) expired(entry cachedResponse) bool {
+	return c.now().Sub(entry.receivedAt) >= c.ttl
+}
+
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]cachedResponse{}
+}
+
+func (t *responseCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := t.next.RoundTrip(req)
+		t.cache.clear()
+		return resp, err
+	}
+	if isNoStore(req.Header) {
+		return t.next.RoundTrip(req)
+	}
+	key := req.Method + " " + req.URL.String() + " " + t.credentialsHash(req.Header)
+	if entry, ok := t.cache.get(key); ok {
+		return entry.response(req), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || isNoStore(resp.Header) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	t.cache.put(key, cachedResponse{status: resp.StatusCode, header: resp.Header.Clone(), body: body, receivedAt: t.cache.now()})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// Returns the hash of the credentials of the request, which are not kept in memory
+// in clear.
+func (t *responseCacheTransport) credentialsHash(header http.Header) string {
+	hash := sha256.New()
+	for _, name := range t.credentialHeaders {
+		for _, value := range header.Values(name) {
+			hash.Write([]byte(name + ": " + value + "\n"))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (e cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+func isNoStore(header http.Header) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package equinix
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCacheTransport(t *testing.T) {
+	// given
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit := atomic.AddInt32(&hits, 1)
+		if r.URL.Path == "/no-store" {
+			w.Header().Set("Cache-Control", "private, no-store")
+		}
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.RequestURI() + " " + strconv.Itoa(int(hit))))
+	}))
+	defer server.Close()
+	clock := &fakeClock{now: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)}
+	config := Config{ResponseCacheTTL: time.Minute, now: clock.Now}
+	client := &http.Client{Transport: config.serviceTransport("ne", http.DefaultTransport)}
+	send := func(method, path string, header http.Header) string {
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		assert.NoError(t, err)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return string(body)
+	}
+	// when
+	first := send(http.MethodGet, "/devices", nil)
+	second := send(http.MethodGet, "/devices", nil)
+	// then
+	assert.Equal(t, "GET /devices 1", first)
+	assert.Equal(t, first, second, "Second identical GET is served from cache")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	// when
+	assert.Equal(t, "GET /devices?page=2 2", send(http.MethodGet, "/devices?page=2", nil), "Requests to other URLs are sent")
+	assert.Equal(t, "GET /devices 3", send(http.MethodGet, "/devices", http.Header{"Cache-Control": {"no-store"}}), "Requests with no-store are sent")
+	assert.Equal(t, "GET /no-store 4", send(http.MethodGet, "/no-store", nil))
+	assert.Equal(t, "GET /no-store 5", send(http.MethodGet, "/no-store", nil), "Responses with no-store are not cached")
+
+	// when
+	clock.Advance(time.Minute)
+	// then
+	assert.Equal(t, "GET /devices 6", send(http.MethodGet, "/devices", nil), "Expired responses are not served")
+	assert.Equal(t, "GET /devices 6", send(http.MethodGet, "/devices", nil))
+
+	// when
+	assert.Equal(t, "POST /devices 7", send(http.MethodPost, "/devices", nil), "Mutations are sent")
+	assert.Equal(t, "POST /devices 8", send(http.MethodPost, "/devices", nil), "Mutations are not cached")
+	// then
+	assert.Equal(t, "GET /devices 9", send(http.MethodGet, "/devices", nil), "Mutations clear the cache")
+}
+
+func TestResponseCacheTransport_errorsNotCached(t *testing.T) {
+	// given
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	transport := newResponseCacheTransport(time.Minute, nil, http.DefaultTransport)
+	client := &http.Client{Transport: transport}
+	// when
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	}
+	// then
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits), "Error responses are not cached")
+	assert.True(t, isNoStore(http.Header{"Cache-Control": {"max-age=0, No-Store"}}))
+	assert.False(t, isNoStore(http.Header{"Cache-Control": {"no-cache"}}))
+}
+
+func TestResponseCacheTransport_credentialsRotated(t *testing.T) {
+	// given
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+	config := Config{BaseURL: server.URL, Token: "initial-token", ResponseCacheTTL: time.Minute}
+	assert.NoError(t, config.Load(context.Background()))
+	client, err := config.ServiceHTTPClient("ne")
+	assert.NoError(t, err)
+	get := func() {
+		resp, err := client.Get(server.URL + "/devices")
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	// when
+	get()
+	get()
+	assert.NoError(t, config.UpdateCredentials("rotated-token"))
+	get()
+	// then
+	assert.Equal(t, []string{"Bearer initial-token", "Bearer rotated-token"}, authorizations, "Responses are not served to other credentials")
+}
+
+func TestResponseCacheTransport_expiredEvicted(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	clock := &fakeClock{now: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)}
+	transport := newResponseCacheTransport(time.Minute, clock.Now, http.DefaultTransport)
+	client := &http.Client{Transport: transport}
+	get := func(path string) {
+		resp, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	// when
+	get("/a")
+	get("/b")
+	clock.Advance(time.Minute)
+	get("/c")
+	// then
+	assert.Len(t, transport.cache.entries, 1, "Expired responses are dropped")
+	// when
+	clock.Advance(time.Minute)
+	get("/c")
+	// then
+	assert.Len(t, transport.cache.entries, 1, "Expired response is replaced")
+}
@@ -0,0 +1,60 @@
+package equinix
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// responseSizeLimitTransport is a RoundTripper failing the responses whose body is
+// larger than the limit, so that a misbehaving endpoint cannot exhaust the memory of
+// the provider. Responses announcing a larger body fail right away, others once more
+// than limit bytes of their body are read.
+type responseSizeLimitTransport struct {
+	limit int64
+	next  http.RoundTripper
+}
+
+func (t *responseSizeLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		return resp, err
+	}
+	if resp.ContentLength > t.limit {
+		resp.Body.Close()
+		return nil, responseTooLargeError(req, t.limit)
+	}
+	resp.Body = &limitedBody{
+		ReadCloser: resp.Body,
+		remaining:  t.limit,
+		err:        responseTooLargeError(req, t.limit),
+	}
+	return resp, nil
+}
+
+func responseTooLargeError(req *http.Request, limit int64) error {
+	return fmt.Errorf("response body of %s %s exceeds the maximum size of %d bytes", req.Method, req.URL.Redacted(), limit)
+}
+
+// limitedBody returns its error once more than the remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	err       error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, b.err
+	}
+	// Reads one byte past the limit to tell a body of exactly limit bytes apart
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), b.err
+	}
+	return n, err
+}
@@ -0,0 +1,71 @@
+package equinix
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseSizeLimitTransport(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := strings.Repeat("x", 64)
+		if r.URL.Path == "/small" {
+			body = strings.Repeat("x", 16)
+		}
+		if r.URL.Query().Get("chunked") != "" {
+			// Flushing before writing the body omits the Content-Length header
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	config := Config{BaseURL: DefaultBaseURL, Token: "token", MaxResponseBytes: 16}
+	assert.NoError(t, config.Load(context.Background()))
+	client := &http.Client{Transport: config.serviceTransport("ne", http.DefaultTransport)}
+	testCases := []struct {
+		name          string
+		path          string
+		expectedError bool
+	}{
+		{"WithinLimit", "/small", false},
+		{"WithinLimitChunked", "/small?chunked=1", false},
+		{"Oversized", "/large", true},
+		{"OversizedChunked", "/large?chunked=1", true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// when
+			var body []byte
+			resp, err := client.Get(server.URL + testCase.path)
+			if err == nil {
+				body, err = io.ReadAll(resp.Body)
+				resp.Body.Close()
+			}
+			// then
+			if !testCase.expectedError {
+				assert.NoError(t, err)
+				assert.Len(t, body, 16, "Bodies within the limit are read fully")
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "exceeds the maximum size of 16 bytes")
+			assert.LessOrEqual(t, len(body), 16, "No more than the limit is read")
+		})
+	}
+}
+
+func TestConfig_Load_invalidMaxResponseBytes(t *testing.T) {
+	// given
+	config := Config{BaseURL: DefaultBaseURL, Token: "token", MaxResponseBytes: -1}
+	// when
+	err := config.Load(context.Background())
+	// then
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "'maxResponseBytes'")
+}
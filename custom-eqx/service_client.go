@@ -0,0 +1,66 @@
+package equinix
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ServiceHTTPClient returns an HTTP client sending authorized requests to the given
+// service, one of "ecx", "fabric", "metal" or "ne", through the same transports as
+// the API clients. It can be used for endpoints which the API clients do not cover,
+// once the configuration is loaded.
+func (c *Config) ServiceHTTPClient(service string) (*http.Client, error) {
+	if c.serviceBase == nil {
+		return nil, fmt.Errorf("configuration must be loaded before creating an HTTP client")
+	}
+	switch service {
+	case "ecx", "fabric", "ne":
+		client := c.newServiceHTTPClient(service, c.tokenSource, c.serviceBaseTransport(service))
+		client.Transport = &defaultHeadersTransport{
+			headers: map[string]string{"User-Agent": c.serviceUserAgent(service)},
+			next:    client.Transport,
+		}
+		return client, nil
+	case "metal":
+		// Equinix Metal requests are authorized with the auth token, which the
+		// Metal API client sets on every request
+		client := c.newMetalHTTPClient(c.serviceBaseTransport("metal")).StandardClient()
+		client.Transport = &defaultHeadersTransport{
+			headers: map[string]string{
+				"User-Agent":       c.metalUserAgent,
+				"X-Auth-Token":     c.AuthToken,
+				"X-Consumer-Token": c.metalConsumerToken(),
+			},
+			next: client.Transport,
+		}
+		return client, nil
+	}
+	return nil, fmt.Errorf("service must be one of: ecx, fabric, metal, ne, got: %q", service)
+}
+
+func (c *Config) serviceUserAgent(service string) string {
+	switch service {
+	case "ecx":
+		return c.ecxUserAgent
+	case "ne":
+		return c.neUserAgent
+	}
+	return c.fullUserAgent("equinix/fabric-go")
+}
+
+// defaultHeadersTransport is a RoundTripper setting the headers which are not
+// already set on the request.
+type defaultHeadersTransport struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (t *defaultHeadersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,112 @@
+package equinix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ServiceHTTPClient(t *testing.T) {
+	// given
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+	config := Config{BaseURL: server.URL, Token: "token", AuthToken: "auth-token"}
+	assert.NoError(t, config.Load(context.Background()))
+	testCases := []struct {
+		service          string
+		expectedHeader   string
+		expectedValue    string
+		userAgentProduct string
+	}{
+		{"ne", "Authorization", "Bearer token", "equinix/ecx-go"},
+		{"ecx", "Authorization", "Bearer token", "equinix/ecx-go"},
+		{"fabric", "Authorization", "Bearer token", "equinix/fabric-go"},
+		{"metal", "X-Auth-Token", "auth-token", "packngo/"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.service, func(t *testing.T) {
+			// when
+			client, err := config.ServiceHTTPClient(testCase.service)
+			assert.NoError(t, err)
+			resp, err := client.Get(server.URL + "/ad-hoc")
+			// then
+			assert.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, testCase.expectedValue, received.Get(testCase.expectedHeader), "Request is authorized")
+			assert.True(t, strings.HasPrefix(received.Get("User-Agent"), "HashiCorp Terraform/"), "User-Agent is set")
+			assert.Contains(t, received.Get("User-Agent"), testCase.userAgentProduct)
+		})
+	}
+}
+
+func TestConfig_ServiceHTTPClient_errors(t *testing.T) {
+	// given
+	config := Config{BaseURL: "https://api.example.com", Token: "token"}
+	// when
+	_, err := config.ServiceHTTPClient("ne")
+	// then
+	assert.EqualError(t, err, "configuration must be loaded before creating an HTTP client")
+	// given
+	assert.NoError(t, config.Load(context.Background()))
+	// when
+	_, err = config.ServiceHTTPClient("unknown")
+	// then
+	assert.EqualError(t, err, `service must be one of: ecx, fabric, metal, ne, got: "unknown"`)
+}
+
+func TestConfig_ServiceHTTPClient_sharedServiceState(t *testing.T) {
+	// given
+	var hits int32
+	failing := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	config := Config{BaseURL: server.URL, Token: "token", ResponseCacheTTL: time.Minute, CircuitBreakerThreshold: 1}
+	assert.NoError(t, config.Load(context.Background()))
+	reader, err := config.ServiceHTTPClient("ne")
+	assert.NoError(t, err)
+	writer, err := config.ServiceHTTPClient("ne")
+	assert.NoError(t, err)
+	send := func(client *http.Client, method string) error {
+		req, _ := http.NewRequest(method, server.URL+"/ad-hoc", nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	// when
+	assert.NoError(t, send(reader, http.MethodGet))
+	assert.NoError(t, send(writer, http.MethodPost))
+	assert.NoError(t, send(reader, http.MethodGet))
+	// then
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits), "Mutations of a client clear the cache of the others")
+
+	// when
+	atomic.StoreInt32(&failing, 1)
+	assert.NoError(t, send(writer, http.MethodPost))
+	_, errLoaded := config.ne.GetSSHPublicKeys()
+	errAdHoc := send(reader, http.MethodGet)
+	// then
+	assert.True(t, errors.Is(errAdHoc, errCircuitOpen), "Circuit opened by a client is open for the others")
+	assert.Error(t, errLoaded)
+	assert.Contains(t, errLoaded.Error(), errCircuitOpen.Error(), "Circuit is open for the clients created in Load")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&hits))
+}
@@ -0,0 +1,38 @@
+package equinix
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// signingTransport is a RoundTripper passing every request to a signer right before
+// it is sent, once its headers and body are final. The signer can read the body,
+// which is restored before the request is sent.
+type signingTransport struct {
+	signer func(*http.Request) error
+	next   http.RoundTripper
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	req = req.Clone(req.Context())
+	if body != nil {
+		setRequestBody(req, body)
+	}
+	if err := t.signer(req); err != nil {
+		return nil, fmt.Errorf("unable to sign %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	if body != nil {
+		setRequestBody(req, body)
+	}
+	return t.next.RoundTrip(req)
+}
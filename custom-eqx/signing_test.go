@@ -0,0 +1,77 @@
+package equinix
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hmacSignature(key string, parts ...[]byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	for _, part := range parts {
+		mac.Write(part)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSigningTransport(t *testing.T) {
+	// given
+	var receivedSignature, expectedSignature, receivedEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		receivedSignature = r.Header.Get("X-Signature")
+		receivedEncoding = r.Header.Get("Content-Encoding")
+		expectedSignature = hmacSignature("key", []byte(r.Method), []byte(r.URL.Path), body)
+	}))
+	defer server.Close()
+	config := Config{
+		GzipRequestThreshold: 10,
+		RequestSigner: func(req *http.Request) error {
+			var body []byte
+			if req.Body != nil {
+				var err error
+				if body, err = io.ReadAll(req.Body); err != nil {
+					return err
+				}
+			}
+			req.Header.Set("X-Signature", hmacSignature("key", []byte(req.Method), []byte(req.URL.Path), body))
+			return nil
+		},
+	}
+	client := &http.Client{Transport: config.serviceTransport("ne", http.DefaultTransport)}
+	// when
+	resp, err := client.Post(server.URL+"/ne/v1/devices", "application/json", strings.NewReader(`{"name": "device-name"}`))
+	// then
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "gzip", receivedEncoding, "Body is compressed before signing")
+	assert.NotEmpty(t, receivedSignature)
+	assert.Equal(t, expectedSignature, receivedSignature, "Signature is computed over the final request")
+}
+
+func TestSigningTransport_error(t *testing.T) {
+	// given
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+	errSigning := errors.New("no signing key")
+	config := Config{RequestSigner: func(req *http.Request) error { return errSigning }}
+	client := &http.Client{Transport: config.serviceTransport("ne", http.DefaultTransport)}
+	// when
+	_, err := client.Get(server.URL + "/ne/v1/devices")
+	// then
+	assert.True(t, errors.Is(err, errSigning), "Signing error is returned")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&hits), "Unsigned request is not sent")
+}
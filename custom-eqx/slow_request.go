@@ -0,0 +1,38 @@
+package equinix
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// slowRequestTransport is a RoundTripper logging a warning for the requests of a
+// service that take longer than the threshold. Only the URL path is logged, as
+// the query may carry secrets.
+type slowRequestTransport struct {
+	service   string
+	threshold time.Duration
+	now       func() time.Time
+	next      http.RoundTripper
+}
+
+func newSlowRequestTransport(service string, threshold time.Duration, now func() time.Time, next http.RoundTripper) *slowRequestTransport {
+	if now == nil {
+		now = time.Now
+	}
+	return &slowRequestTransport{service: service, threshold: threshold, now: now, next: next}
+}
+
+func (t *slowRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.now()
+	resp, err := t.next.RoundTrip(req)
+	if duration := t.now().Sub(start); duration > t.threshold {
+		requestID := ""
+		if err == nil {
+			requestID = resp.Header.Get("X-Request-Id")
+		}
+		log.Printf("[WARN] Slow %s API request: %s %s took %s (threshold %s), request ID: %q",
+			t.service, req.Method, req.URL.Path, duration, t.threshold, requestID)
+	}
+	return resp, err
+}
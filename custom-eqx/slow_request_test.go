@@ -0,0 +1,41 @@
+package equinix
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowRequestTransport(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Header().Set("X-Request-Id", "req-123")
+	}))
+	defer server.Close()
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	config := Config{SlowRequestThreshold: 20 * time.Millisecond}
+	client := &http.Client{Transport: config.serviceTransport("ne", http.DefaultTransport)}
+	// when
+	for _, path := range []string{"/fast", "/slow?token=secret"} {
+		resp, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	// then
+	output := logs.String()
+	assert.Contains(t, output, "[WARN] Slow ne API request: GET /slow took", "Slow request is logged")
+	assert.Contains(t, output, `request ID: "req-123"`, "Request ID is logged")
+	assert.NotContains(t, output, "/fast", "Fast request is not logged")
+	assert.NotContains(t, output, "secret", "Query is not logged")
+}
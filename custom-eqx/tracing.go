@@ -0,0 +1,9 @@
+package equinix
+
+import "net/http"
+
+// RequestTracer traces the API requests sent by the provider, by wrapping the
+// transport of every API client.
+type RequestTracer interface {
+	TraceTransport(service string, next http.RoundTripper) http.RoundTripper
+}
@@ -0,0 +1,46 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingRequestTracer struct {
+	requests map[string]int
+}
+
+func (r *recordingRequestTracer) TraceTransport(service string, next http.RoundTripper) http.RoundTripper {
+	return &recordingTransport{tracer: r, service: service, next: next}
+}
+
+type recordingTransport struct {
+	tracer  *recordingRequestTracer
+	service string
+	next    http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.tracer.requests[t.service]++
+	return t.next.RoundTrip(req)
+}
+
+func TestConfig_Load_requestTracer(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	tracer := &recordingRequestTracer{requests: map[string]int{}}
+	config := Config{BaseURL: server.URL, Token: "token", RequestTracer: tracer}
+	assert.NoError(t, config.Load(context.Background()))
+	// when
+	_, err := config.ne.GetSSHPublicKeys()
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"ne": 1}, tracer.requests, "Requests are traced per service")
+}
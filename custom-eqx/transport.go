@@ -0,0 +1,208 @@
+package equinix
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"golang.org/x/net/http/httpproxy"
+	xoauth2 "golang.org/x/oauth2"
+)
+
+// newServiceHTTPClient creates the HTTP client of the given service. Requests are
+// authorized with tokens from the token source, presented in the configured auth
+// header, and sent over the base transport.
+func (c *Config) newServiceHTTPClient(service string, tokenSource xoauth2.TokenSource, base http.RoundTripper) *http.Client {
+	var transport http.RoundTripper = &tokenHeaderTransport{
+		source: tokenSource,
+		name:   c.authHeaderName(),
+		format: c.authHeaderFormat(),
+		next:   c.serviceTransport(service, base),
+	}
+	return &http.Client{
+		Transport: logging.NewTransport("Equinix", transport),
+		Timeout:   c.serviceRequestTimeout(service),
+	}
+}
+
+// serviceTransport wraps the base transport with the service specific RoundTrippers.
+func (c *Config) serviceTransport(service string, base http.RoundTripper) http.RoundTripper {
+	transport := base
+	if c.RequestSigner != nil {
+		transport = &signingTransport{signer: c.RequestSigner, next: transport}
+	}
+	if c.concurrencyLimiter != nil {
+		transport = &concurrencyLimitTransport{limiter: c.concurrencyLimiter, next: transport}
+	}
+	if c.MaxResponseBytes > 0 {
+		transport = &responseSizeLimitTransport{limit: c.MaxResponseBytes, next: transport}
+	}
+	if c.GzipRequestThreshold > 0 {
+		transport = &gzipRequestTransport{threshold: c.GzipRequestThreshold, next: transport}
+	}
+	if len(c.ExtraHeaders) > 0 {
+		transport = &extraHeadersTransport{headers: c.ExtraHeaders, next: transport}
+	}
+	transport = &rateLimitTransport{
+		service:   service,
+		state:     &c.rateLimit,
+		threshold: c.RateLimitWarningThreshold,
+		next:      transport,
+	}
+	transport = &deprecationTransport{service: service, state: &c.deprecations, next: transport}
+	if c.MetricsSink != nil {
+		transport = newMetricsTransport(service, c.MetricsSink, c.now, transport)
+	}
+	if c.AuditSink != nil {
+		transport = newAuditTransport(service, c.AuditSink, c.now, transport)
+	}
+	if c.SlowRequestThreshold > 0 {
+		transport = newSlowRequestTransport(service, c.SlowRequestThreshold, c.now, transport)
+	}
+	if c.CircuitBreakerThreshold > 0 {
+		transport = &circuitBreakerTransport{service: service, breaker: c.serviceState(service).breaker, next: transport}
+	}
+	if c.ResponseCacheTTL > 0 {
+		transport = &responseCacheTransport{
+			cache:             c.serviceState(service).cache,
+			credentialHeaders: []string{c.authHeaderName(), "X-Auth-Token"},
+			next:              transport,
+		}
+	}
+	if c.RequestTracer != nil {
+		transport = c.RequestTracer.TraceTransport(service, transport)
+	}
+	return transport
+}
+
+// serviceState is the state of a service shared by all of its clients, i.e. the ones
+// created in Load and by ServiceHTTPClient, so that they see the same open circuit and
+// cached responses.
+type serviceState struct {
+	breaker *circuitBreaker
+	cache   *responseCache
+}
+
+// Returns the state of the service, creating it when its first client is created.
+func (c *Config) serviceState(service string) *serviceState {
+	c.serviceStatesMu.Lock()
+	defer c.serviceStatesMu.Unlock()
+	if state, ok := c.serviceStates[service]; ok {
+		return state
+	}
+	state := &serviceState{}
+	if c.CircuitBreakerThreshold > 0 {
+		state.breaker = newCircuitBreaker(c.CircuitBreakerThreshold, c.CircuitBreakerWindow, c.CircuitBreakerCooldown, c.now)
+	}
+	if c.ResponseCacheTTL > 0 {
+		state.cache = newResponseCache(c.ResponseCacheTTL, c.now)
+	}
+	if c.serviceStates == nil {
+		c.serviceStates = map[string]*serviceState{}
+	}
+	c.serviceStates[service] = state
+	return state
+}
+
+// tlsVersions maps the supported MinTLSVersion values to the TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// transportSettings are the settings of the Config a base transport is created with.
+type transportSettings struct {
+	proxy                 string
+	minTLSVersion         string
+	responseHeaderTimeout time.Duration
+	dialTimeout           time.Duration
+	disableHTTP2          bool
+}
+
+// sharedTransports are the base transports of the Configs with ShareTransport, keyed
+// by the settings they were created with.
+var (
+	sharedTransportsMu sync.Mutex
+	sharedTransports   = map[transportSettings]*http.Transport{}
+)
+
+// baseTransport returns the base transport of the API clients, which is shared with
+// the other Configs with the same settings when ShareTransport is set.
+func (c *Config) baseTransport() (*http.Transport, error) {
+	if !c.ShareTransport {
+		return c.newTransport()
+	}
+	settings := transportSettings{
+		proxy:                 c.Proxy,
+		minTLSVersion:         c.MinTLSVersion,
+		responseHeaderTimeout: c.ResponseHeaderTimeout,
+		dialTimeout:           c.DialTimeout,
+		disableHTTP2:          c.DisableHTTP2,
+	}
+	sharedTransportsMu.Lock()
+	defer sharedTransportsMu.Unlock()
+	if transport, ok := sharedTransports[settings]; ok {
+		return transport, nil
+	}
+	transport, err := c.newTransport()
+	if err != nil {
+		return nil, err
+	}
+	sharedTransports[settings] = transport
+	return transport, nil
+}
+
+// newTransport creates the base transport shared by all API clients.
+func (c *Config) newTransport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxy, err := c.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+	if c.MinTLSVersion != "" {
+		version, ok := tlsVersions[c.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("'minTLSVersion' must be one of: 1.0, 1.1, 1.2, 1.3, got: %q", c.MinTLSVersion)
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.MinVersion = version
+	}
+	transport.ResponseHeaderTimeout = c.ResponseHeaderTimeout
+	if c.DialTimeout > 0 {
+		dialer := &net.Dialer{Timeout: c.DialTimeout, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+	}
+	if c.DisableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport, nil
+}
+
+// proxyFunc returns the proxy selection function for the base transport. It
+// follows http.ProxyFromEnvironment semantics, including NO_PROXY handling,
+// with the configured Proxy taking precedence over HTTP_PROXY and HTTPS_PROXY.
+func (c *Config) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if c.Proxy != "" {
+		if u, err := url.Parse(c.Proxy); err != nil || u.Host == "" {
+			return nil, fmt.Errorf("'proxy' must be a valid URL, got: %q", c.Proxy)
+		}
+		proxyConfig.HTTPProxy = c.Proxy
+		proxyConfig.HTTPSProxy = c.Proxy
+	}
+	proxyURL := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyURL(req.URL)
+	}, nil
+}
@@ -0,0 +1,204 @@
+package equinix
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport_proxyFunc(t *testing.T) {
+	// given
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("HTTP_PROXY", "")
+	testCases := []struct {
+		name     string
+		noProxy  string
+		expected string
+	}{
+		{"NoProxyNotSet", "", "http://proxy.example.com:3128"},
+		{"NoProxyNotCoveringHost", "example.com,10.0.0.0/8", "http://proxy.example.com:3128"},
+		{"NoProxyCoveringHost", "example.com,.equinix.com", ""},
+		{"NoProxyWildcard", "*", ""},
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.equinix.com/ne/v1/devices", nil)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Setenv("NO_PROXY", testCase.noProxy)
+			config := Config{Proxy: "http://proxy.example.com:3128"}
+			// when
+			proxyFunc, err := config.proxyFunc()
+			assert.NoError(t, err, "proxyFunc does not return an error")
+			proxyURL, err := proxyFunc(req)
+			// then
+			assert.NoError(t, err, "Proxy selection does not return an error")
+			if testCase.expected == "" {
+				assert.Nil(t, proxyURL, "Request bypasses the proxy")
+			} else {
+				assert.Equal(t, testCase.expected, proxyURL.String(), "Request uses the proxy")
+			}
+		})
+	}
+}
+
+func TestTransport_proxyFunc_fromEnvironment(t *testing.T) {
+	// given
+	t.Setenv("HTTPS_PROXY", "http://env-proxy.example.com:3128")
+	t.Setenv("NO_PROXY", "")
+	req, _ := http.NewRequest(http.MethodGet, "https://api.equinix.com/ne/v1/devices", nil)
+	config := Config{}
+	// when
+	proxyFunc, err := config.proxyFunc()
+	assert.NoError(t, err, "proxyFunc does not return an error")
+	proxyURL, err := proxyFunc(req)
+	// then
+	assert.NoError(t, err, "Proxy selection does not return an error")
+	assert.Equal(t, "http://env-proxy.example.com:3128", proxyURL.String(), "Request uses the proxy from environment")
+}
+
+func TestTransport_proxyFunc_invalid(t *testing.T) {
+	// given
+	config := Config{Proxy: "::not a url"}
+	// when
+	_, err := config.proxyFunc()
+	// then
+	assert.Error(t, err, "proxyFunc returns an error for invalid proxy")
+}
+
+func TestTransport_responseHeaderTimeout(t *testing.T) {
+	// given
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	config := Config{ResponseHeaderTimeout: 50 * time.Millisecond}
+	transport, err := config.newTransport()
+	assert.NoError(t, err, "newTransport does not return an error")
+	client := &http.Client{Transport: transport, Timeout: 5 * time.Second}
+	// when
+	start := time.Now()
+	_, err = client.Get(server.URL)
+	// then
+	var netErr net.Error
+	assert.True(t, errors.As(err, &netErr) && netErr.Timeout(), "Request fails with a timeout error")
+	assert.Contains(t, err.Error(), "timeout awaiting response headers", "Response header timeout is reported")
+	assert.Less(t, time.Since(start), 5*time.Second, "Request is aborted before overall timeout")
+}
+
+func TestTransport_dialTimeout(t *testing.T) {
+	// given
+	config := Config{DialTimeout: 100 * time.Millisecond}
+	transport, err := config.newTransport()
+	assert.NoError(t, err, "newTransport does not return an error")
+	client := &http.Client{Transport: transport, Timeout: 5 * time.Second}
+	// when
+	start := time.Now()
+	resp, err := client.Get("http://10.255.255.1:81")
+	// then
+	if err == nil {
+		resp.Body.Close()
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) || opErr.Op != "dial" {
+		t.Skipf("Unroutable address is reachable from this network: %v", err)
+	}
+	assert.True(t, opErr.Timeout(), "Dial fails with a timeout error")
+	assert.Less(t, time.Since(start), time.Second, "Connection attempt is aborted after the dial timeout")
+}
+
+func TestTransport_minTLSVersion(t *testing.T) {
+	// given
+	config := Config{MinTLSVersion: "1.3"}
+	// when
+	transport, err := config.newTransport()
+	// then
+	assert.NoError(t, err, "newTransport does not return an error")
+	assert.Equal(t, uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion, "Minimum TLS version is set")
+	// given
+	config = Config{MinTLSVersion: "1.4"}
+	// when
+	_, err = config.newTransport()
+	// then
+	assert.EqualError(t, err, `'minTLSVersion' must be one of: 1.0, 1.1, 1.2, 1.3, got: "1.4"`)
+}
+
+func TestTransport_disableHTTP2(t *testing.T) {
+	// given
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	for _, disabled := range []bool{false, true} {
+		config := Config{DisableHTTP2: disabled}
+		transport, err := config.newTransport()
+		assert.NoError(t, err, "newTransport does not return an error")
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+		// when
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		// then
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, !disabled, transport.ForceAttemptHTTP2, "HTTP/2 attempt reflects the flag")
+		if disabled {
+			assert.NotNil(t, transport.TLSNextProto, "TLSNextProto is set to disable HTTP/2")
+			assert.Equal(t, "HTTP/1.1", resp.Proto, "HTTP/1.1 is used when HTTP/2 is disabled")
+		} else {
+			assert.Equal(t, "HTTP/2.0", resp.Proto, "HTTP/2 is used by default")
+		}
+	}
+}
+
+func TestConfig_Load_shareTransport(t *testing.T) {
+	for _, shared := range []bool{false, true} {
+		// given
+		var mu sync.Mutex
+		connections := 0
+		var authorizations []string
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+		}))
+		server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				mu.Lock()
+				defer mu.Unlock()
+				connections++
+			}
+		}
+		server.Start()
+		for _, token := range []string{"token-1", "token-2"} {
+			config := Config{BaseURL: server.URL, Token: token, ShareTransport: shared}
+			assert.NoError(t, config.Load(context.Background()))
+			client, err := config.ServiceHTTPClient("ne")
+			assert.NoError(t, err)
+			// when
+			resp, err := client.Get(server.URL + "/ad-hoc")
+			// then
+			assert.NoError(t, err)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		server.Close()
+		expectedConnections := 2
+		if shared {
+			expectedConnections = 1
+		}
+		assert.Equal(t, expectedConnections, connections, "Configs sharing the transport reuse its connections")
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, authorizations, "Requests are authorized by their own Config")
+	}
+}